@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/model"
+	"github.com/deepfence/ThreatMapper/deepfence_utils/artifactcache"
+	"github.com/deepfence/golang_deepfence_sdk/utils/log"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// PurgeArtifactCache backs the admin
+// POST /settings/artifact-cache/purge?prefix=... endpoint: it removes every
+// cached vulnerability DB / SBOM / posture-provider entry under prefix from
+// the shared artifact cache, e.g. to force every replica to re-download
+// after a known-bad cache entry was written.
+func (h *Handler) PurgeArtifactCache(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+
+	cache, err := artifactcache.NewFromEnv(r.Context())
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	removed, err := cache.Purge(r.Context(), prefix)
+	if err != nil {
+		log.Error().Msgf("artifact cache purge failed: %v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	log.Info().Msgf("purged %d artifact cache entries under prefix %q", removed, prefix)
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "artifact cache purged"})
+}
+
+// GetArtifactCacheStats backs GET /settings/artifact-cache/stats, exposing
+// the cache hit ratio so cold-start time and repeated-scan reuse are
+// observable. artifactcache.NewFromEnv returns this process's shared Store
+// singleton, so this reflects every cache lookup deepfence_server itself has
+// made; it does not aggregate deepfence_worker's replicas, which publish
+// their own hit/miss counts via the deepfence_artifactcache_lookups_total
+// Prometheus metric instead.
+func (h *Handler) GetArtifactCacheStats(w http.ResponseWriter, r *http.Request) {
+	cache, err := artifactcache.NewFromEnv(r.Context())
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	stats := cache.Stats()
+	httpext.JSON(w, http.StatusOK, struct {
+		artifactcache.Stats
+		HitRatio float64
+	}{stats, stats.HitRatio()})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/model"
+	"github.com/deepfence/golang_deepfence_sdk/utils/directory"
+	"github.com/deepfence/golang_deepfence_sdk/utils/log"
+	"github.com/go-chi/chi/v5"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// ListIntegrationExecutions backs GET /integration/{integration_id}/executions.
+func (h *Handler) ListIntegrationExecutions(w http.ResponseWriter, r *http.Request) {
+	integrationID, err := strconv.ParseInt(chi.URLParam(r, "integration_id"), 10, 32)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	executions, err := model.ListExecutionsForIntegration(ctx, pgClient, int32(integrationID))
+	if err != nil {
+		log.Error().Msgf(err.Error())
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, executions)
+}
+
+// GetExecution backs GET /execution/{execution_id}.
+func (h *Handler) GetExecution(w http.ResponseWriter, r *http.Request) {
+	executionID, err := strconv.ParseInt(chi.URLParam(r, "execution_id"), 10, 64)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	execution, err := model.GetExecution(ctx, pgClient, executionID)
+	if err != nil {
+		log.Error().Msgf(err.Error())
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, execution)
+}
+
+// GetExecutionLog backs GET /execution/{execution_id}/log.
+func (h *Handler) GetExecutionLog(w http.ResponseWriter, r *http.Request) {
+	executionID, err := strconv.ParseInt(chi.URLParam(r, "execution_id"), 10, 64)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	logLines, err := model.GetExecutionLog(ctx, pgClient, executionID)
+	if err != nil {
+		log.Error().Msgf(err.Error())
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, logLines)
+}
+
+// StopExecution backs POST /execution/{execution_id}/stop.
+func (h *Handler) StopExecution(w http.ResponseWriter, r *http.Request) {
+	executionID, err := strconv.ParseInt(chi.URLParam(r, "execution_id"), 10, 64)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	if err := model.StopExecution(ctx, pgClient, executionID); err != nil {
+		log.Error().Msgf(err.Error())
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "execution stopped"})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	api_messages "github.com/deepfence/ThreatMapper/deepfence_server/constants/api-messages"
 	"github.com/deepfence/ThreatMapper/deepfence_server/model"
 	"github.com/deepfence/ThreatMapper/deepfence_server/pkg/integration"
+	"github.com/deepfence/ThreatMapper/deepfence_server/pkg/secrets"
 	"github.com/deepfence/golang_deepfence_sdk/utils/directory"
 	"github.com/deepfence/golang_deepfence_sdk/utils/log"
 	"github.com/go-chi/chi/v5"
@@ -32,7 +34,7 @@ func (h *Handler) AddIntegration(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = integration.GetIntegration(req.IntegrationType, b)
+	i, err := integration.GetIntegration(req.IntegrationType, b)
 	if err != nil {
 		log.Error().Msgf("%v", err)
 		respondError(&BadDecoding{err}, w)
@@ -58,13 +60,19 @@ func (h *Handler) AddIntegration(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// check if integration is valid
-	/*err = i.SendNotification("validating integration")
-	if err != nil {
-		log.Error().Msgf("%v", err)
-		respondError(&ValidatorError{err}, w)
-		return
-	}*/
+	// ?validate=true opts into testing the integration synchronously before it
+	// is saved, so a bad Slack webhook or misconfigured S3 bucket comes back
+	// as an immediate 400 with the upstream error instead of a silent success
+	validate, _ := strconv.ParseBool(r.URL.Query().Get("validate"))
+	if validate {
+		if err := i.TestIntegration(integration.SampleNotificationRequest(req.NotificationType)); err != nil {
+			log.Error().Msgf("integration validation failed: %v", err)
+			req.LastValidationError = err.Error()
+			respondError(&ValidatorError{err}, w)
+			return
+		}
+		req.LastValidationError = ""
+	}
 
 	user, statusCode, _, _, err := h.GetUserFromJWT(r.Context())
 	if err != nil {
@@ -72,6 +80,21 @@ func (h *Handler) AddIntegration(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// envelope-encrypt webhook URLs, API tokens, SMTP passwords, etc. before
+	// they ever reach Postgres; RedactSensitiveFieldsInConfig alone only
+	// protects reads, not a DB dump or backup
+	keyProvider, err := secrets.NewKeyProvider(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	req.Config, err = secrets.EncryptConfigMap(ctx, keyProvider, req.Config, integration.SensitiveConfigFields(req.IntegrationType))
+	if err != nil {
+		log.Error().Msgf(err.Error())
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
 	// store the integration in db
 	err = req.CreateIntegration(ctx, pgClient, user.ID)
 	if err != nil {
@@ -79,10 +102,85 @@ func (h *Handler) AddIntegration(w http.ResponseWriter, r *http.Request) {
 		respondError(&InternalServerError{err}, w)
 		return
 	}
+
+	// only ?validate=true fires a tracked sample delivery: saving without it
+	// must never send a real alert, so the execution + retry machinery below
+	// stays behind the same gate as the synchronous pre-save check above
+	if validate {
+		executionID, err := model.CreateExecution(ctx, pgClient, model.ExecutionVendorIntegration, req.ID,
+			model.ExecutionTriggerManual, nil)
+		if err != nil {
+			log.Error().Msgf("failed to record integration execution: %s", err.Error())
+		} else {
+			go func() {
+				// the sync validate check above already performed one live
+				// TestIntegration send and it succeeded (otherwise we'd have
+				// 400'd before saving), so the first tracked attempt must not
+				// send again - only retries beyond it call TestIntegration.
+				firstAttempt := true
+				deliverCtx := directory.WithGlobalContext(context.Background())
+				deliverErr := model.DeliverWithRetry(deliverCtx, pgClient, executionID, model.DefaultRetryPolicy, func() error {
+					if firstAttempt {
+						firstAttempt = false
+						return nil
+					}
+					return i.TestIntegration(integration.SampleNotificationRequest(req.NotificationType))
+				})
+				validationError := ""
+				if deliverErr != nil {
+					log.Error().Msgf("integration %d delivery failed after retries: %s", req.ID, deliverErr.Error())
+					validationError = deliverErr.Error()
+				}
+				if err := model.UpdateIntegrationValidationError(deliverCtx, pgClient, req.ID, validationError); err != nil {
+					log.Error().Msgf("failed to record integration %d validation outcome: %s", req.ID, err.Error())
+				}
+			}()
+		}
+	}
+
 	httpext.JSON(w, http.StatusOK, api_messages.SuccessIntegrationCreated)
 
 }
 
+// TestIntegration backs POST /integration/test: it builds the integration
+// from the request body exactly like AddIntegration does, but never persists
+// anything. It synthesizes a sample payload for the configured
+// NotificationType and performs a live send, so the caller gets an immediate
+// 400 with the upstream error (Slack 404, SMTP auth failure, ...) before
+// ever saving the integration.
+func (h *Handler) TestIntegration(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req model.IntegrationAddReq
+	err := httpext.DecodeJSON(r, httpext.NoQueryParams, MaxPostRequestSize, &req)
+	if err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	i, err := integration.GetIntegration(req.IntegrationType, b)
+	if err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	if err := i.TestIntegration(integration.SampleNotificationRequest(req.NotificationType)); err != nil {
+		log.Error().Msgf("integration test failed: %v", err)
+		respondError(&ValidatorError{err}, w)
+		return
+	}
+
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "integration test succeeded"})
+}
+
 func (h *Handler) GetIntegrations(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req model.IntegrationListReq
@@ -101,28 +199,42 @@ func (h *Handler) GetIntegrations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	keyProvider, err := secrets.NewKeyProvider(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
 	var integrationList []model.IntegrationListResp
-	for _, integration := range integrations {
+	for _, integ := range integrations {
 		var config map[string]interface{}
 		var filters map[string][]string
 
-		err = json.Unmarshal(integration.Config, &config)
+		err = json.Unmarshal(integ.Config, &config)
 		if err != nil {
 			log.Error().Msgf(err.Error())
 			respondError(&InternalServerError{err}, w)
 			return
 		}
 
-		err = json.Unmarshal(integration.Filters, &filters)
+		err = json.Unmarshal(integ.Filters, &filters)
 		if err != nil {
 			log.Error().Msgf(err.Error())
 			respondError(&InternalServerError{err}, w)
 			return
 		}
+
+		config, err = secrets.DecryptConfigMap(ctx, keyProvider, config, integration.SensitiveConfigFields(integ.IntegrationType))
+		if err != nil {
+			log.Error().Msgf(err.Error())
+			respondError(&InternalServerError{err}, w)
+			return
+		}
+
 		newIntegration := model.IntegrationListResp{
-			ID:               integration.ID,
-			IntegrationType:  integration.IntegrationType,
-			NotificationType: integration.Resource,
+			ID:               integ.ID,
+			IntegrationType:  integ.IntegrationType,
+			NotificationType: integ.Resource,
 			Config:           config,
 			Filters:          filters,
 		}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/model"
+	"github.com/deepfence/golang_deepfence_sdk/utils/directory"
+	"github.com/deepfence/golang_deepfence_sdk/utils/log"
+	"github.com/go-chi/chi/v5"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// AddScheduledTask backs POST /settings/scheduled-task: a user-defined cron
+// expression plus an action (VulnerabilityScan, SecretScan, ComplianceScan,
+// SendReport, SendNotification) and a JSON payload of target node filters,
+// registry IDs, or scan config.
+func (h *Handler) AddScheduledTask(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req model.ScheduledTaskReq
+	if err := httpext.DecodeJSON(r, httpext.NoQueryParams, MaxPostRequestSize, &req); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	user, statusCode, _, _, err := h.GetUserFromJWT(r.Context())
+	if err != nil {
+		respondWithErrorCode(err, w, statusCode)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	id, err := model.CreateScheduledTask(ctx, pgClient, req, user.ID)
+	if err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&ValidatorError{err}, w)
+		return
+	}
+
+	httpext.JSON(w, http.StatusOK, model.ScheduledTaskResp{ID: id})
+}
+
+// ListScheduledTasks backs GET /settings/scheduled-task.
+func (h *Handler) ListScheduledTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	tasks, err := model.ListScheduledTasks(ctx, pgClient)
+	if err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, tasks)
+}
+
+// UpdateScheduledTask backs PUT /settings/scheduled-task/{scheduled_task_id}.
+func (h *Handler) UpdateScheduledTask(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id, err := parseScheduledTaskID(r)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	var req model.ScheduledTaskReq
+	if err := httpext.DecodeJSON(r, httpext.NoQueryParams, MaxPostRequestSize, &req); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	if err := model.UpdateScheduledTask(ctx, pgClient, id, req); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&ValidatorError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "scheduled task updated"})
+}
+
+// DeleteScheduledTask backs DELETE /settings/scheduled-task/{scheduled_task_id}.
+func (h *Handler) DeleteScheduledTask(w http.ResponseWriter, r *http.Request) {
+	id, err := parseScheduledTaskID(r)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	if err := model.DeleteScheduledTask(ctx, pgClient, id); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "scheduled task deleted"})
+}
+
+// SetScheduledTaskEnabled backs
+// POST /settings/scheduled-task/{scheduled_task_id}/enable and .../disable.
+func (h *Handler) SetScheduledTaskEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	id, err := parseScheduledTaskID(r)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	if err := model.SetScheduledTaskEnabled(ctx, pgClient, id, enabled); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "scheduled task updated"})
+}
+
+// RunScheduledTaskNow backs POST /settings/scheduled-task/{scheduled_task_id}/run.
+func (h *Handler) RunScheduledTaskNow(w http.ResponseWriter, r *http.Request) {
+	id, err := parseScheduledTaskID(r)
+	if err != nil {
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	if err := model.RunScheduledTaskNow(ctx, pgClient, id); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "scheduled task triggered"})
+}
+
+func parseScheduledTaskID(r *http.Request) (int32, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "scheduled_task_id"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/model"
+	"github.com/deepfence/ThreatMapper/deepfence_server/pkg/secrets"
+	"github.com/deepfence/golang_deepfence_sdk/utils/directory"
+	"github.com/deepfence/golang_deepfence_sdk/utils/log"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+// RotateSecretsReq is the request body for POST /settings/secrets/rotate.
+// OldKeyRef identifies the KEK that is being retired (the previous local KEK
+// secret, or the previous AWS/GCP/Vault key ID/ARN/name), since once an
+// operator points *_KEY_ID/LocalKEKEnvVar at the new KEK there is nowhere
+// else left to recover it from.
+type RotateSecretsReq struct {
+	OldKeyRef string `json:"old_key_ref"`
+}
+
+// RotateSecrets backs POST /settings/secrets/rotate: it rewraps every
+// integration's envelope-encrypted config fields from the KEK identified by
+// OldKeyRef to whichever KEK secrets.NewKeyProvider resolves right now, so a
+// KEK rotation never requires re-encrypting the underlying ciphertext, only
+// its wrapped DEK.
+func (h *Handler) RotateSecrets(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req RotateSecretsReq
+	if err := httpext.DecodeJSON(r, httpext.NoQueryParams, MaxPostRequestSize, &req); err != nil {
+		log.Error().Msgf("%v", err)
+		respondError(&BadDecoding{err}, w)
+		return
+	}
+	if req.OldKeyRef == "" {
+		respondError(&ValidatorError{fmt.Errorf("old_key_ref is required")}, w)
+		return
+	}
+
+	ctx := directory.WithGlobalContext(r.Context())
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	newProvider, err := secrets.NewKeyProvider(ctx)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+	oldProvider, err := secrets.NewKeyProviderForRef(ctx, req.OldKeyRef)
+	if err != nil {
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	rewrapped, err := model.RotateIntegrationSecrets(ctx, pgClient, oldProvider, newProvider)
+	if err != nil {
+		log.Error().Msgf("secret rotation failed: %v", err)
+		respondError(&InternalServerError{err}, w)
+		return
+	}
+
+	httpext.JSON(w, http.StatusOK, model.MessageResponse{Message: "rotated secrets for integrations"})
+	log.Info().Msgf("secrets: rotated %d integration config(s)", rewrapped)
+}
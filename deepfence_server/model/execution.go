@@ -0,0 +1,278 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/deepfence/golang_deepfence_sdk/utils/log"
+	postgresqlDb "github.com/deepfence/ThreatMapper/deepfence_utils/postgresql/postgresql-db"
+)
+
+// ErrRetriesExhausted is returned by RetryPolicy.NextDelay once an Execution
+// has used up its allotted attempts.
+var ErrRetriesExhausted = errors.New("execution retries exhausted")
+
+// ExecutionStatus is the lifecycle state of a single Execution row.
+type ExecutionStatus string
+
+const (
+	ExecutionStatusPending ExecutionStatus = "pending"
+	ExecutionStatusRunning ExecutionStatus = "running"
+	ExecutionStatusSuccess ExecutionStatus = "success"
+	ExecutionStatusError   ExecutionStatus = "error"
+	ExecutionStatusStopped ExecutionStatus = "stopped"
+)
+
+// ExecutionVendorType identifies what kind of thing an Execution tracks.
+type ExecutionVendorType string
+
+const (
+	ExecutionVendorIntegration ExecutionVendorType = "integration"
+	ExecutionVendorScan        ExecutionVendorType = "scan"
+	ExecutionVendorCron        ExecutionVendorType = "cron"
+)
+
+// ExecutionTrigger identifies what caused an Execution to fire.
+type ExecutionTrigger string
+
+const (
+	ExecutionTriggerManual   ExecutionTrigger = "manual"
+	ExecutionTriggerSchedule ExecutionTrigger = "schedule"
+	ExecutionTriggerEvent    ExecutionTrigger = "event"
+)
+
+// Execution is the record of one firing of a scheduled task or one delivery
+// of an integration; retried attempts of that delivery share the record and
+// are counted in RunCount.
+type Execution struct {
+	ID         int64               `json:"id"`
+	VendorType ExecutionVendorType `json:"vendor_type"`
+	VendorID   int32               `json:"vendor_id"`
+	Trigger    ExecutionTrigger    `json:"trigger"`
+	Status     ExecutionStatus     `json:"status"`
+	StartTime  time.Time           `json:"start_time"`
+	EndTime    *time.Time          `json:"end_time,omitempty"`
+	RunCount   int32               `json:"run_count"`
+	ExtraAttrs json.RawMessage     `json:"extra_attrs,omitempty"`
+}
+
+// ExecutionLogAppend is a single line appended to an execution's streamed
+// log blob, returned in order by GetExecutionLog.
+type ExecutionLogAppend struct {
+	Time time.Time `json:"time"`
+	Line string    `json:"line"`
+}
+
+// RetryPolicy is exponential backoff with a hard cap on attempts.
+type RetryPolicy struct {
+	MaxAttempts  int           `json:"max_attempts"`
+	InitialDelay time.Duration `json:"initial_delay"`
+	MaxDelay     time.Duration `json:"max_delay"`
+}
+
+// DefaultRetryPolicy is used by integrations and scheduled tasks that don't
+// specify their own.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  5,
+	InitialDelay: 2 * time.Second,
+	MaxDelay:     2 * time.Minute,
+}
+
+// NextDelay returns the backoff to wait before the given attempt (1-indexed).
+// It returns ErrRetriesExhausted once attempt exceeds MaxAttempts.
+func (p RetryPolicy) NextDelay(attempt int) (time.Duration, error) {
+	if attempt > p.MaxAttempts {
+		return 0, ErrRetriesExhausted
+	}
+	delay := p.InitialDelay * time.Duration(math.Pow(2, float64(attempt-1)))
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay, nil
+}
+
+// CreateExecution inserts a pending Execution row and returns its ID. Callers
+// (cronscheduler ticks, integration deliveries) transition it through
+// UpdateExecutionStatus as the underlying work progresses.
+func CreateExecution(ctx context.Context, pgClient *postgresqlDb.Queries, vendorType ExecutionVendorType,
+	vendorID int32, trigger ExecutionTrigger, extraAttrs json.RawMessage) (int64, error) {
+
+	row, err := pgClient.CreateExecution(ctx, postgresqlDb.CreateExecutionParams{
+		VendorType: string(vendorType),
+		VendorID:   vendorID,
+		Trigger:    string(trigger),
+		Status:     string(ExecutionStatusPending),
+		ExtraAttrs: extraAttrs,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return row.ID, nil
+}
+
+// UpdateExecutionStatus transitions an Execution and, for terminal statuses,
+// stamps EndTime.
+func UpdateExecutionStatus(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64, status ExecutionStatus) error {
+	return pgClient.UpdateExecutionStatus(ctx, postgresqlDb.UpdateExecutionStatusParams{
+		ID:     executionID,
+		Status: string(status),
+	})
+}
+
+// IncrementExecutionRunCount bumps RunCount by one, called once per actual
+// send attempt (see DeliverWithRetry).
+func IncrementExecutionRunCount(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64) error {
+	return pgClient.IncrementExecutionRunCount(ctx, executionID)
+}
+
+// AppendExecutionLog streams one more line into an execution's log blob so
+// GET /execution/{id}/log can tail it while the job is still running.
+func AppendExecutionLog(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64, line string) error {
+	return pgClient.AppendExecutionLog(ctx, postgresqlDb.AppendExecutionLogParams{
+		ID:   executionID,
+		Line: line,
+	})
+}
+
+// GetExecution fetches a single Execution by ID.
+func GetExecution(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64) (Execution, error) {
+	row, err := pgClient.GetExecution(ctx, executionID)
+	if err != nil {
+		return Execution{}, err
+	}
+	return executionFromRow(row), nil
+}
+
+// ListExecutionsForIntegration backs GET /integration/{id}/executions.
+func ListExecutionsForIntegration(ctx context.Context, pgClient *postgresqlDb.Queries, integrationID int32) ([]Execution, error) {
+	rows, err := pgClient.GetExecutionsByVendor(ctx, postgresqlDb.GetExecutionsByVendorParams{
+		VendorType: string(ExecutionVendorIntegration),
+		VendorID:   integrationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	executions := make([]Execution, 0, len(rows))
+	for _, row := range rows {
+		executions = append(executions, executionFromRow(row))
+	}
+	return executions, nil
+}
+
+// StopExecution marks a running Execution as stopped, backing
+// POST /execution/{id}/stop.
+func StopExecution(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64) error {
+	return UpdateExecutionStatus(ctx, pgClient, executionID, ExecutionStatusStopped)
+}
+
+// GetExecutionLog backs GET /execution/{id}/log.
+func GetExecutionLog(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64) ([]ExecutionLogAppend, error) {
+	rows, err := pgClient.GetExecutionLog(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]ExecutionLogAppend, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, ExecutionLogAppend{Time: row.CreatedAt, Line: row.Line})
+	}
+	return lines, nil
+}
+
+// ErrExecutionStopped is returned by DeliverWithRetry when POST
+// /execution/{id}/stop flips the row to stopped while a retry loop is still
+// in flight, so the loop's own status writes don't clobber it back to
+// running/success/error.
+var ErrExecutionStopped = errors.New("execution stopped")
+
+// DeliverWithRetry drives an Execution through running -> success/error,
+// retrying send under policy with AppendExecutionLog recording each failed
+// attempt.
+// Before every attempt, and before writing a terminal status, it re-reads the
+// Execution row so a concurrent StopExecution call actually halts the loop
+// instead of being overwritten by the next status transition.
+func DeliverWithRetry(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64,
+	policy RetryPolicy, send func() error) error {
+
+	if err := UpdateExecutionStatus(ctx, pgClient, executionID, ExecutionStatusRunning); err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if stopped, err := executionStopped(ctx, pgClient, executionID); err != nil {
+			log.Error().Err(err).Msg("failed to check execution status")
+		} else if stopped {
+			return ErrExecutionStopped
+		}
+
+		if err := IncrementExecutionRunCount(ctx, pgClient, executionID); err != nil {
+			log.Error().Err(err).Msg("failed to record execution attempt")
+		}
+		lastErr = send()
+		if lastErr == nil {
+			if stopped, err := executionStopped(ctx, pgClient, executionID); err == nil && stopped {
+				return ErrExecutionStopped
+			}
+			if err := UpdateExecutionStatus(ctx, pgClient, executionID, ExecutionStatusSuccess); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		if logErr := AppendExecutionLog(ctx, pgClient, executionID,
+			"attempt "+strconv.Itoa(attempt)+" failed: "+lastErr.Error()); logErr != nil {
+			log.Error().Err(logErr).Msg("failed to append execution log")
+		}
+
+		delay, retryErr := policy.NextDelay(attempt + 1)
+		if errors.Is(retryErr, ErrRetriesExhausted) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	if stopped, err := executionStopped(ctx, pgClient, executionID); err == nil && stopped {
+		return ErrExecutionStopped
+	}
+	if err := UpdateExecutionStatus(ctx, pgClient, executionID, ExecutionStatusError); err != nil {
+		return err
+	}
+	return lastErr
+}
+
+// executionStopped reports whether executionID has been flipped to
+// ExecutionStatusStopped, e.g. by StopExecution, since DeliverWithRetry last
+// checked.
+func executionStopped(ctx context.Context, pgClient *postgresqlDb.Queries, executionID int64) (bool, error) {
+	execution, err := GetExecution(ctx, pgClient, executionID)
+	if err != nil {
+		return false, err
+	}
+	return execution.Status == ExecutionStatusStopped, nil
+}
+
+func executionFromRow(row postgresqlDb.Execution) Execution {
+	execution := Execution{
+		ID:         row.ID,
+		VendorType: ExecutionVendorType(row.VendorType),
+		VendorID:   row.VendorID,
+		Trigger:    ExecutionTrigger(row.Trigger),
+		Status:     ExecutionStatus(row.Status),
+		StartTime:  row.StartTime,
+		RunCount:   row.RunCount,
+		ExtraAttrs: row.ExtraAttrs,
+	}
+	if row.EndTime.Valid {
+		execution.EndTime = &row.EndTime.Time
+	}
+	return execution
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: time.Second,
+		MaxDelay:     5 * time.Second,
+	}
+
+	cases := []struct {
+		attempt   int
+		wantDelay time.Duration
+		wantErr   error
+	}{
+		{1, time.Second, nil},
+		{2, 2 * time.Second, nil},
+		{3, 4 * time.Second, nil},
+		{4, 5 * time.Second, nil}, // would be 8s uncapped, clamped to MaxDelay
+		{5, 0, ErrRetriesExhausted},
+	}
+
+	for _, c := range cases {
+		delay, err := policy.NextDelay(c.attempt)
+		if !errors.Is(err, c.wantErr) {
+			t.Errorf("NextDelay(%d) error = %v, want %v", c.attempt, err, c.wantErr)
+		}
+		if delay != c.wantDelay {
+			t.Errorf("NextDelay(%d) = %v, want %v", c.attempt, delay, c.wantDelay)
+		}
+	}
+}
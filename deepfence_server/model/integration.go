@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/pkg/integration"
+	postgresqlDb "github.com/deepfence/ThreatMapper/deepfence_utils/postgresql/postgresql-db"
+)
+
+// IntegrationAddReq is the request body for POST /integration and
+// POST /integration/test.
+type IntegrationAddReq struct {
+	ID                  int32                  `json:"id"`
+	IntegrationType     string                 `json:"integration_type"`
+	NotificationType    string                 `json:"notification_type"`
+	Config              map[string]interface{} `json:"config"`
+	Filters             map[string][]string    `json:"filters"`
+	LastValidationError string                 `json:"-"`
+}
+
+// IntegrationListReq is the request body for GET /integration.
+type IntegrationListReq struct {
+	IntegrationType string `json:"integration_type"`
+}
+
+// IntegrationListResp is the response shape for GET /integration.
+type IntegrationListResp struct {
+	ID               int32                  `json:"id"`
+	IntegrationType  string                 `json:"integration_type"`
+	NotificationType string                 `json:"notification_type"`
+	Config           map[string]interface{} `json:"config"`
+	Filters          map[string][]string    `json:"filters"`
+}
+
+// RedactSensitiveFieldsInConfig blanks out secret config values (webhook
+// URLs, API tokens, ...) before a list response leaves the server.
+func (r *IntegrationListResp) RedactSensitiveFieldsInConfig() {
+	for _, field := range integration.SensitiveConfigFields(r.IntegrationType) {
+		if _, ok := r.Config[field]; ok {
+			r.Config[field] = "******"
+		}
+	}
+}
+
+// IntegrationExists reports whether an integration of this type+notification
+// type already exists.
+func (req IntegrationAddReq) IntegrationExists(ctx context.Context, pgClient *postgresqlDb.Queries) (bool, error) {
+	return pgClient.IntegrationExists(ctx, postgresqlDb.IntegrationExistsParams{
+		IntegrationType: req.IntegrationType,
+		Resource:        req.NotificationType,
+	})
+}
+
+// CreateIntegration persists req, storing Config and Filters as their
+// already-encrypted/marshaled JSON form.
+func (req *IntegrationAddReq) CreateIntegration(ctx context.Context, pgClient *postgresqlDb.Queries, userID int64) error {
+	config, err := json.Marshal(req.Config)
+	if err != nil {
+		return err
+	}
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		return err
+	}
+	row, err := pgClient.CreateIntegration(ctx, postgresqlDb.CreateIntegrationParams{
+		IntegrationType:     req.IntegrationType,
+		Resource:            req.NotificationType,
+		Config:              config,
+		Filters:             filters,
+		CreatedByUserID:     userID,
+		LastValidationError: req.LastValidationError,
+	})
+	if err != nil {
+		return err
+	}
+	req.ID = row.ID
+	return nil
+}
+
+// GetIntegrations returns every integration matching req.IntegrationType, or
+// every integration if it is empty.
+func (req IntegrationListReq) GetIntegrations(ctx context.Context, pgClient *postgresqlDb.Queries) ([]postgresqlDb.Integration, error) {
+	if req.IntegrationType == "" {
+		return pgClient.GetIntegrations(ctx)
+	}
+	return pgClient.GetIntegrationsByType(ctx, req.IntegrationType)
+}
+
+// DeleteIntegration removes an integration by ID.
+func DeleteIntegration(ctx context.Context, pgClient *postgresqlDb.Queries, id int32) error {
+	return pgClient.DeleteIntegration(ctx, id)
+}
+
+// UpdateIntegrationValidationError records the outcome of the most recent
+// validation attempt on the integration row itself. GET /integration does
+// not return it; IntegrationListResp has no field for it.
+func UpdateIntegrationValidationError(ctx context.Context, pgClient *postgresqlDb.Queries, id int32, validationError string) error {
+	return pgClient.UpdateIntegrationValidationError(ctx, postgresqlDb.UpdateIntegrationValidationErrorParams{
+		ID:                  id,
+		LastValidationError: validationError,
+	})
+}
@@ -0,0 +1,269 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	postgresqlDb "github.com/deepfence/ThreatMapper/deepfence_utils/postgresql/postgresql-db"
+	"github.com/robfig/cron/v3"
+)
+
+// overlapCheckCount is how many upcoming fire times are compared between two
+// schedules when checking for overlap.
+const overlapCheckCount = 5
+
+// MinScheduleInterval rejects cron expressions that would fire more often
+// than this, so one tenant can't accidentally (or deliberately) hammer the
+// scan/notification pipeline with a `* * * * * *` schedule.
+const MinScheduleInterval = 1 * time.Minute
+
+// ScheduledTaskAction is the set of actions a user-defined schedule can run.
+type ScheduledTaskAction string
+
+const (
+	ScheduledTaskVulnerabilityScan ScheduledTaskAction = "VulnerabilityScan"
+	ScheduledTaskSecretScan        ScheduledTaskAction = "SecretScan"
+	ScheduledTaskComplianceScan    ScheduledTaskAction = "ComplianceScan"
+	ScheduledTaskSendReport        ScheduledTaskAction = "SendReport"
+	ScheduledTaskSendNotification  ScheduledTaskAction = "SendNotification"
+)
+
+// ScheduledTaskReq is the request body for POST/PUT /settings/scheduled-task.
+type ScheduledTaskReq struct {
+	CronExpr    string              `json:"cron_expr"`
+	Action      ScheduledTaskAction `json:"action"`
+	Payload     json.RawMessage     `json:"payload"`
+	Description string              `json:"description"`
+	IsEnabled   bool                `json:"is_enabled"`
+}
+
+// ScheduledTaskResp is the response shape for the scheduled-task endpoints.
+type ScheduledTaskResp struct {
+	ID          int32               `json:"id"`
+	CronExpr    string              `json:"cron_expr"`
+	Action      ScheduledTaskAction `json:"action"`
+	Payload     json.RawMessage     `json:"payload"`
+	Description string              `json:"description"`
+	IsEnabled   bool                `json:"is_enabled"`
+	CreatedByID int64               `json:"created_by_user_id"`
+}
+
+// scheduledTaskNotifyChannel is the Postgres NOTIFY channel published to on
+// every create/update/delete/enable/disable.
+const scheduledTaskNotifyChannel = "scheduled_task_updates"
+
+var validActions = map[ScheduledTaskAction]bool{
+	ScheduledTaskVulnerabilityScan: true,
+	ScheduledTaskSecretScan:        true,
+	ScheduledTaskComplianceScan:    true,
+	ScheduledTaskSendReport:        true,
+	ScheduledTaskSendNotification:  true,
+}
+
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// validate parses the cron expression, rejects schedules firing more than
+// once a minute, and rejects one whose fire times collide with another
+// enabled schedule. excludeID is the task's own ID on update (0 on create).
+func (req ScheduledTaskReq) validate(ctx context.Context, pgClient *postgresqlDb.Queries, excludeID int32) error {
+	if !validActions[req.Action] {
+		return fmt.Errorf("unsupported scheduled task action %q", req.Action)
+	}
+	schedule, err := cronParser.Parse(req.CronExpr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", req.CronExpr, err)
+	}
+	now := time.Now()
+	if fireTimes := nextFireTimes(schedule, now, overlapCheckCount); minGap(fireTimes) < MinScheduleInterval {
+		return fmt.Errorf("cron expression %q fires more often than the minimum interval of %s", req.CronExpr, MinScheduleInterval)
+	}
+
+	others, err := pgClient.ListEnabledScheduledTasks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID == excludeID {
+			continue
+		}
+		otherSchedule, err := cronParser.Parse(other.CronExpr)
+		if err != nil {
+			continue // a pre-existing row should always parse, but never block on one that doesn't
+		}
+		if schedulesOverlap(schedule, otherSchedule, now) {
+			return fmt.Errorf("cron expression %q overlaps with existing scheduled task %d (%q)", req.CronExpr, other.ID, other.CronExpr)
+		}
+	}
+	return nil
+}
+
+// schedulesOverlap reports whether a and b would ever fire within
+// MinScheduleInterval of each other, comparing every one of a's next
+// overlapCheckCount fire times against every one of b's.
+func schedulesOverlap(a, b cron.Schedule, start time.Time) bool {
+	aTimes := nextFireTimes(a, start, overlapCheckCount)
+	bTimes := nextFireTimes(b, start, overlapCheckCount)
+	for _, at := range aTimes {
+		for _, bt := range bTimes {
+			diff := at.Sub(bt)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff < MinScheduleInterval {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nextFireTimes(s cron.Schedule, start time.Time, n int) []time.Time {
+	times := make([]time.Time, n)
+	t := start
+	for i := 0; i < n; i++ {
+		t = s.Next(t)
+		times[i] = t
+	}
+	return times
+}
+
+// minGap returns the smallest gap between consecutive fire times. A schedule
+// like "0,30 0 * * * *" needs every consecutive pair checked, not just the
+// first, since where its short gap falls in the sequence depends purely on
+// when validate happens to run. The time from now until the first fire is
+// not a gap between two fires and is not counted.
+func minGap(fireTimes []time.Time) time.Duration {
+	min := time.Duration(1<<63 - 1)
+	for i := 1; i < len(fireTimes); i++ {
+		if gap := fireTimes[i].Sub(fireTimes[i-1]); gap < min {
+			min = gap
+		}
+	}
+	return min
+}
+
+// CreateScheduledTask validates req and inserts a new user-defined schedule,
+// then publishes on scheduledTaskNotifyChannel so every worker replica picks
+// it up immediately.
+func CreateScheduledTask(ctx context.Context, pgClient *postgresqlDb.Queries, req ScheduledTaskReq, userID int64) (int32, error) {
+	if err := req.validate(ctx, pgClient, 0); err != nil {
+		return 0, err
+	}
+	row, err := pgClient.CreateScheduledTask(ctx, postgresqlDb.CreateScheduledTaskParams{
+		CronExpr:        req.CronExpr,
+		Action:          string(req.Action),
+		Payload:         req.Payload,
+		Description:     req.Description,
+		IsEnabled:       req.IsEnabled,
+		CreatedByUserID: userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := notifyScheduledTaskChange(ctx, pgClient, scheduledTaskNotification{Type: "changed"}); err != nil {
+		return row.ID, err
+	}
+	return row.ID, nil
+}
+
+// UpdateScheduledTask validates req and overwrites an existing schedule.
+func UpdateScheduledTask(ctx context.Context, pgClient *postgresqlDb.Queries, id int32, req ScheduledTaskReq) error {
+	if err := req.validate(ctx, pgClient, id); err != nil {
+		return err
+	}
+	if err := pgClient.UpdateScheduledTask(ctx, postgresqlDb.UpdateScheduledTaskParams{
+		ID:          id,
+		CronExpr:    req.CronExpr,
+		Action:      string(req.Action),
+		Payload:     req.Payload,
+		Description: req.Description,
+		IsEnabled:   req.IsEnabled,
+	}); err != nil {
+		return err
+	}
+	return notifyScheduledTaskChange(ctx, pgClient, scheduledTaskNotification{Type: "changed"})
+}
+
+// SetScheduledTaskEnabled flips the enable/disable flag without touching the
+// rest of the schedule.
+func SetScheduledTaskEnabled(ctx context.Context, pgClient *postgresqlDb.Queries, id int32, enabled bool) error {
+	if err := pgClient.SetScheduledTaskEnabled(ctx, postgresqlDb.SetScheduledTaskEnabledParams{
+		ID:        id,
+		IsEnabled: enabled,
+	}); err != nil {
+		return err
+	}
+	return notifyScheduledTaskChange(ctx, pgClient, scheduledTaskNotification{Type: "changed"})
+}
+
+// DeleteScheduledTask removes a user-defined schedule.
+func DeleteScheduledTask(ctx context.Context, pgClient *postgresqlDb.Queries, id int32) error {
+	if err := pgClient.DeleteScheduledTask(ctx, id); err != nil {
+		return err
+	}
+	return notifyScheduledTaskChange(ctx, pgClient, scheduledTaskNotification{Type: "changed"})
+}
+
+// GetScheduledTask fetches a single schedule.
+func GetScheduledTask(ctx context.Context, pgClient *postgresqlDb.Queries, id int32) (ScheduledTaskResp, error) {
+	row, err := pgClient.GetScheduledTask(ctx, id)
+	if err != nil {
+		return ScheduledTaskResp{}, err
+	}
+	return scheduledTaskFromRow(row), nil
+}
+
+// ListScheduledTasks returns every schedule visible to the caller's tenant.
+func ListScheduledTasks(ctx context.Context, pgClient *postgresqlDb.Queries) ([]ScheduledTaskResp, error) {
+	rows, err := pgClient.ListScheduledTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]ScheduledTaskResp, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, scheduledTaskFromRow(row))
+	}
+	return tasks, nil
+}
+
+// scheduledTaskNotification is the payload published on
+// scheduledTaskNotifyChannel. "changed" triggers a full reconcile, "run_now"
+// triggers one immediate off-cycle run of ID.
+type scheduledTaskNotification struct {
+	Type string `json:"type"`
+	ID   int32  `json:"id,omitempty"`
+}
+
+// RunScheduledTaskNow publishes a "run_now" notification for id, backing the
+// "run now" control.
+func RunScheduledTaskNow(ctx context.Context, pgClient *postgresqlDb.Queries, id int32) error {
+	if _, err := pgClient.GetScheduledTask(ctx, id); err != nil {
+		return err
+	}
+	return notifyScheduledTaskChange(ctx, pgClient, scheduledTaskNotification{Type: "run_now", ID: id})
+}
+
+func notifyScheduledTaskChange(ctx context.Context, pgClient *postgresqlDb.Queries, notification scheduledTaskNotification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	return pgClient.NotifyChannel(ctx, postgresqlDb.NotifyChannelParams{
+		Channel: scheduledTaskNotifyChannel,
+		Payload: string(payload),
+	})
+}
+
+func scheduledTaskFromRow(row postgresqlDb.ScheduledTask) ScheduledTaskResp {
+	return ScheduledTaskResp{
+		ID:          row.ID,
+		CronExpr:    row.CronExpr,
+		Action:      ScheduledTaskAction(row.Action),
+		Payload:     row.Payload,
+		Description: row.Description,
+		IsEnabled:   row.IsEnabled,
+		CreatedByID: row.CreatedByUserID,
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestSchedulesOverlap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mustParse := func(t *testing.T, expr string) cron.Schedule {
+		t.Helper()
+		s, err := cronParser.Parse(expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", expr, err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{"identical schedules overlap", "0 * * * * *", "0 * * * * *", true},
+		{"offset by a few seconds still overlaps", "0 * * * * *", "5 * * * * *", true},
+		{"10m and 15m intervals eventually collide", "0 */10 * * * *", "0 */15 * * * *", true},
+		{"far apart hourly schedules never collide", "0 0 * * * *", "0 30 * * * *", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := mustParse(t, tc.a)
+			b := mustParse(t, tc.b)
+			if got := schedulesOverlap(a, b, start); got != tc.want {
+				t.Errorf("schedulesOverlap(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMinGapAcrossConsecutiveFireTimes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 15, 0, time.UTC)
+
+	mustParse := func(t *testing.T, expr string) cron.Schedule {
+		t.Helper()
+		s, err := cronParser.Parse(expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", expr, err)
+		}
+		return s
+	}
+
+	// Fires at :00:00 and :00:30 of every hour. From start the first fire is
+	// 00:00:30 and the next is 01:00:00, 59m30s later, so checking only the
+	// first pair would miss the 30s gap between 01:00:00 and 01:00:30.
+	schedule := mustParse(t, "0,30 0 * * * *")
+	fireTimes := nextFireTimes(schedule, start, overlapCheckCount)
+	if got := minGap(fireTimes); got >= MinScheduleInterval {
+		t.Errorf("minGap = %v, want < %v", got, MinScheduleInterval)
+	}
+}
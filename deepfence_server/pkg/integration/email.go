@@ -0,0 +1,29 @@
+package integration
+
+import (
+	"fmt"
+	"net/smtp"
+)
+
+type email struct {
+	SMTPHost string `json:"smtp_host"`
+	SMTPPort string `json:"smtp_port"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Password string `json:"password"`
+}
+
+func newEmail(b []byte) (*email, error) {
+	var e email
+	if err := decodeConfig(b, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (e *email) TestIntegration(message []map[string]interface{}) error {
+	auth := smtp.PlainAuth("", e.From, e.Password, e.SMTPHost)
+	body := fmt.Sprintf("Subject: ThreatMapper alert\r\n\r\nThreatMapper alert: %d finding(s)\r\n", len(message))
+	addr := fmt.Sprintf("%s:%s", e.SMTPHost, e.SMTPPort)
+	return smtp.SendMail(addr, auth, e.From, []string{e.To}, []byte(body))
+}
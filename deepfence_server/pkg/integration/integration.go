@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Integration is implemented by every notification channel ThreatMapper can
+// deliver findings to. TestIntegration sends message through the same
+// formatting/delivery path used for real alerts, so POST /integration/test
+// and AddIntegration's initial delivery execution exercise exactly what a
+// real scan result would hit.
+type Integration interface {
+	TestIntegration(message []map[string]interface{}) error
+}
+
+// GetIntegration unmarshals the raw request body into the config struct for
+// integrationType and returns the matching Integration implementation.
+func GetIntegration(integrationType string, b []byte) (Integration, error) {
+	switch integrationType {
+	case "slack":
+		return newSlack(b)
+	case "teams":
+		return newTeams(b)
+	case "pagerduty":
+		return newPagerDuty(b)
+	case "opsgenie":
+		return newOpsgenie(b)
+	case "email":
+		return newEmail(b)
+	case "s3":
+		return newS3(b)
+	case "jira":
+		return newJira(b)
+	case "splunk":
+		return newSplunk(b)
+	case "sumologic":
+		return newSumoLogic(b)
+	default:
+		return nil, fmt.Errorf("unsupported integration type: %s", integrationType)
+	}
+}
+
+// decodeConfig is a small helper shared by every integration's constructor:
+// the request body is the full IntegrationAddReq, and Config carries the
+// type-specific settings (webhook_url, api_key, ...) as a nested object.
+func decodeConfig(b []byte, out interface{}) error {
+	var wrapper struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return err
+	}
+	return json.Unmarshal(wrapper.Config, out)
+}
@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type jira struct {
+	URL       string `json:"jira_site_url"`
+	ProjectID string `json:"jira_project_key"`
+	Email     string `json:"email"`
+	APIToken  string `json:"api_token"`
+}
+
+func newJira(b []byte) (*jira, error) {
+	var j jira
+	if err := decodeConfig(b, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (j *jira) TestIntegration(message []map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.ProjectID},
+			"summary":     fmt.Sprintf("ThreatMapper alert: %d finding(s)", len(message)),
+			"issuetype":   map[string]string{"name": "Bug"},
+			"description": "Created by the ThreatMapper Jira integration test.",
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, j.URL+"/rest/api/2/issue", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(j.Email, j.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("jira issue creation failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
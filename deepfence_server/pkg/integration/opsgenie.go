@@ -0,0 +1,23 @@
+package integration
+
+import "fmt"
+
+const opsgenieAlertsURL = "https://api.opsgenie.com/v2/alerts"
+
+type opsgenie struct {
+	APIKey string `json:"api_key"`
+}
+
+func newOpsgenie(b []byte) (*opsgenie, error) {
+	var o opsgenie
+	if err := decodeConfig(b, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (o *opsgenie) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(opsgenieAlertsURL, map[string]interface{}{
+		"message": fmt.Sprintf("ThreatMapper alert: %d finding(s)", len(message)),
+	}, map[string]string{"Authorization": "GenieKey " + o.APIKey})
+}
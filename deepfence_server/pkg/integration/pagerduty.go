@@ -0,0 +1,30 @@
+package integration
+
+import "fmt"
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+type pagerDuty struct {
+	ServiceKey string `json:"service_key"`
+	APIKey     string `json:"api_key"`
+}
+
+func newPagerDuty(b []byte) (*pagerDuty, error) {
+	var p pagerDuty
+	if err := decodeConfig(b, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *pagerDuty) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(pagerDutyEventsURL, map[string]interface{}{
+		"routing_key":  p.ServiceKey,
+		"event_action": "trigger",
+		"payload": map[string]interface{}{
+			"summary":  fmt.Sprintf("ThreatMapper alert: %d finding(s)", len(message)),
+			"source":   "deepfence-threatmapper",
+			"severity": "critical",
+		},
+	}, map[string]string{"Authorization": "Token token=" + p.APIKey})
+}
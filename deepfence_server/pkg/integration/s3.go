@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type s3 struct {
+	AWSAccessKey string `json:"aws_access_key"`
+	AWSSecretKey string `json:"aws_secret_key"`
+	Region       string `json:"aws_region"`
+	Bucket       string `json:"s3_bucket_name"`
+}
+
+func newS3(b []byte) (*s3, error) {
+	var s s3
+	if err := decodeConfig(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *s3) TestIntegration(message []map[string]interface{}) error {
+	client, err := minio.New(fmt.Sprintf("s3.%s.amazonaws.com", s.Region), &minio.Options{
+		Creds:  credentials.NewStaticV4(s.AWSAccessKey, s.AWSSecretKey, ""),
+		Secure: true,
+	})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	key := fmt.Sprintf("threatmapper/integration-test-%d.json", time.Now().UnixNano())
+	_, err = client.PutObject(ctx, s.Bucket, key, bytes.NewReader(payload), int64(len(payload)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
@@ -0,0 +1,48 @@
+package integration
+
+import "time"
+
+// SampleNotificationRequest builds a small, realistic fake record for the
+// given NotificationType so a POST /integration/test (or AddIntegration with
+// ?validate=true) can exercise a live send without waiting for a real scan
+// to turn up a finding. Each Integration implementation's TestIntegration
+// method sends this record through the same formatting/delivery path used
+// for real alerts.
+func SampleNotificationRequest(notificationType string) []map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339)
+	switch notificationType {
+	case "Compliance", "CloudCompliance":
+		return []map[string]interface{}{{
+			"test_category":         "Sample",
+			"test_number":           "TEST-0",
+			"test_desc":             "This is a sample compliance finding sent to validate the integration",
+			"status":                "fail",
+			"node_id":               "sample-node-id",
+			"node_name":             "sample-node",
+			"compliance_check_type": notificationType,
+			"masked":                "false",
+			"updated_at":            now,
+		}}
+	case "Malware":
+		return []map[string]interface{}{{
+			"rule_name":  "Sample-Malware-Rule",
+			"file_name":  "/tmp/sample-malware-file",
+			"severity":   "critical",
+			"node_id":    "sample-node-id",
+			"node_name":  "sample-node",
+			"scan_id":    "sample-scan-id",
+			"updated_at": now,
+		}}
+	default: // Vulnerability
+		return []map[string]interface{}{{
+			"cve_id":            "CVE-0000-0000",
+			"cve_severity":      "critical",
+			"cve_description":   "This is a sample vulnerability sent to validate the integration",
+			"node_id":           "sample-node-id",
+			"node_name":         "sample-node",
+			"scan_id":           "sample-scan-id",
+			"cve_attack_vector": "NETWORK",
+			"updated_at":        now,
+		}}
+	}
+}
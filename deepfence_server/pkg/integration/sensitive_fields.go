@@ -0,0 +1,22 @@
+package integration
+
+// sensitiveConfigFields lists, per IntegrationType, which keys of a
+// config map hold secret material (webhook URLs, API tokens, passwords,
+// ...) that pkg/secrets must envelope-encrypt before the row is persisted.
+var sensitiveConfigFields = map[string][]string{
+	"slack":     {"webhook_url"},
+	"teams":     {"webhook_url"},
+	"pagerduty": {"service_key", "api_key"},
+	"opsgenie":  {"api_key"},
+	"email":     {"password"},
+	"s3":        {"aws_secret_key"},
+	"jira":      {"api_token", "password"},
+	"splunk":    {"token"},
+	"sumologic": {"endpoint_url"},
+}
+
+// SensitiveConfigFields returns the config keys that must be
+// envelope-encrypted at rest for the given integration type.
+func SensitiveConfigFields(integrationType string) []string {
+	return sensitiveConfigFields[integrationType]
+}
@@ -0,0 +1,21 @@
+package integration
+
+import "fmt"
+
+type slack struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+func newSlack(b []byte) (*slack, error) {
+	var s slack
+	if err := decodeConfig(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *slack) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(s.WebhookURL, map[string]interface{}{
+		"text": fmt.Sprintf("ThreatMapper alert: %d finding(s)", len(message)),
+	}, nil)
+}
@@ -0,0 +1,22 @@
+package integration
+
+import "fmt"
+
+type splunk struct {
+	EndpointURL string `json:"endpoint_url"`
+	Token       string `json:"token"`
+}
+
+func newSplunk(b []byte) (*splunk, error) {
+	var s splunk
+	if err := decodeConfig(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *splunk) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(s.EndpointURL, map[string]interface{}{
+		"event": message,
+	}, map[string]string{"Authorization": fmt.Sprintf("Splunk %s", s.Token)})
+}
@@ -0,0 +1,17 @@
+package integration
+
+type sumoLogic struct {
+	EndpointURL string `json:"endpoint_url"`
+}
+
+func newSumoLogic(b []byte) (*sumoLogic, error) {
+	var s sumoLogic
+	if err := decodeConfig(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *sumoLogic) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(s.EndpointURL, message, nil)
+}
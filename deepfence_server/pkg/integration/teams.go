@@ -0,0 +1,21 @@
+package integration
+
+import "fmt"
+
+type teams struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+func newTeams(b []byte) (*teams, error) {
+	var t teams
+	if err := decodeConfig(b, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *teams) TestIntegration(message []map[string]interface{}) error {
+	return postJSON(t.WebhookURL, map[string]interface{}{
+		"text": fmt.Sprintf("ThreatMapper alert: %d finding(s)", len(message)),
+	}, nil)
+}
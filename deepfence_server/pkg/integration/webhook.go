@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookClient is shared by every integration that delivers by POSTing a
+// JSON payload to a configured URL (Slack, Teams, PagerDuty, Opsgenie,
+// Splunk, SumoLogic): only the payload shape and headers differ per vendor.
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON POSTs body to url with the given extra headers and treats any
+// non-2xx response as a delivery failure, so a dead webhook or expired token
+// surfaces as an error TestIntegration/DeliverWithRetry can act on.
+func postJSON(url string, body interface{}, headers map[string]string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook delivery failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// awsKMSProvider wraps/unwraps DEKs with a single AWS KMS CMK, identified by
+// key ARN or alias.
+type awsKMSProvider struct {
+	client *kms.Client
+	keyID  string
+}
+
+// NewAWSKMSProvider loads credentials from the standard AWS SDK chain (env,
+// shared config, instance role) and targets the given CMK.
+func NewAWSKMSProvider(ctx context.Context, keyID string) (KeyProvider, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &awsKMSProvider{client: kms.NewFromConfig(cfg), keyID: keyID}, nil
+}
+
+func (p *awsKMSProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	out, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (p *awsKMSProvider) UnwrapKey(ctx context.Context, wrappedDEK []byte) ([]byte, error) {
+	out, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(p.keyID),
+		CiphertextBlob: wrappedDEK,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+func (p *awsKMSProvider) KeyID() string {
+	return p.keyID
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+// Envelope is one encrypted field: a DEK-encrypted ciphertext plus the DEK
+// itself wrapped under the active KeyProvider.
+type Envelope struct {
+	KeyID      string `json:"key_id"`
+	WrappedDEK []byte `json:"wrapped_dek"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal generates a fresh DEK, AES-GCM-encrypts plaintext under it, and wraps
+// the DEK with provider so the result can be stored as an opaque blob.
+func Seal(ctx context.Context, provider KeyProvider, plaintext []byte) (Envelope, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return Envelope{}, err
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return Envelope{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Envelope{}, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	wrappedDEK, err := provider.WrapKey(ctx, dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		KeyID:      provider.KeyID(),
+		WrappedDEK: wrappedDEK,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Open unwraps the envelope's DEK with provider and decrypts Ciphertext.
+func Open(ctx context.Context, provider KeyProvider, env Envelope) ([]byte, error) {
+	if len(env.WrappedDEK) == 0 {
+		return nil, errors.New("secrets: empty wrapped DEK")
+	}
+	dek, err := provider.UnwrapKey(ctx, env.WrappedDEK)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+}
+
+// Rewrap decrypts env's DEK with oldProvider and re-wraps it with
+// newProvider, without touching Ciphertext. Used by key rotation.
+func Rewrap(ctx context.Context, oldProvider, newProvider KeyProvider, env Envelope) (Envelope, error) {
+	dek, err := oldProvider.UnwrapKey(ctx, env.WrappedDEK)
+	if err != nil {
+		return Envelope{}, err
+	}
+	wrappedDEK, err := newProvider.WrapKey(ctx, dek)
+	if err != nil {
+		return Envelope{}, err
+	}
+	env.KeyID = newProvider.KeyID()
+	env.WrappedDEK = wrappedDEK
+	return env, nil
+}
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	provider, err := newLocalProviderWithSecret("test-kek")
+	if err != nil {
+		t.Fatalf("newLocalProviderWithSecret: %v", err)
+	}
+
+	plaintext := []byte("super secret webhook token")
+	env, err := Seal(ctx, provider, plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	got, err := Open(ctx, provider, env)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("Open() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	ctx := context.Background()
+	oldProvider, err := newLocalProviderWithSecret("old-kek")
+	if err != nil {
+		t.Fatalf("newLocalProviderWithSecret(old): %v", err)
+	}
+	newProvider, err := newLocalProviderWithSecret("new-kek")
+	if err != nil {
+		t.Fatalf("newLocalProviderWithSecret(new): %v", err)
+	}
+
+	plaintext := []byte("rotate me")
+	env, err := Seal(ctx, oldProvider, plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	rewrapped, err := Rewrap(ctx, oldProvider, newProvider, env)
+	if err != nil {
+		t.Fatalf("Rewrap: %v", err)
+	}
+	if rewrapped.KeyID != newProvider.KeyID() {
+		t.Errorf("Rewrap KeyID = %q, want %q", rewrapped.KeyID, newProvider.KeyID())
+	}
+
+	got, err := Open(ctx, newProvider, rewrapped)
+	if err != nil {
+		t.Fatalf("Open after rewrap: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("Open() after rewrap = %q, want %q", got, plaintext)
+	}
+
+	if _, err := Open(ctx, oldProvider, rewrapped); err == nil {
+		t.Error("expected Open with the old provider to fail after rewrap")
+	}
+}
@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// providerEnvVar selects which KeyProvider backs this deployment. Defaults
+// to the local AES-GCM fallback so a fresh install works without a KMS.
+const providerEnvVar = "DEEPFENCE_SECRETS_PROVIDER"
+
+// NewKeyProvider builds the KeyProvider configured for this deployment via
+// DEEPFENCE_SECRETS_PROVIDER (local|aws-kms|gcp-kms|vault-transit) and the
+// matching key env var (DEEPFENCE_SECRETS_AWS_KEY_ID, DEEPFENCE_SECRETS_GCP_KEY_ID,
+// DEEPFENCE_SECRETS_VAULT_KEY_NAME, or LocalKEKEnvVar).
+func NewKeyProvider(ctx context.Context) (KeyProvider, error) {
+	switch Kind(os.Getenv(providerEnvVar)) {
+	case KindAWSKMS:
+		return NewAWSKMSProvider(ctx, os.Getenv("DEEPFENCE_SECRETS_AWS_KEY_ID"))
+	case KindGCPKMS:
+		return NewGCPKMSProvider(ctx, os.Getenv("DEEPFENCE_SECRETS_GCP_KEY_ID"))
+	case KindVaultTransit:
+		return NewVaultTransitProvider(os.Getenv("DEEPFENCE_SECRETS_VAULT_KEY_NAME"))
+	case KindLocal, "":
+		return NewLocalProvider()
+	default:
+		return nil, fmt.Errorf("secrets: unknown provider kind %q", os.Getenv(providerEnvVar))
+	}
+}
+
+// NewKeyProviderForRef builds a KeyProvider of the same kind as the
+// currently configured deployment, but pointed at a specific key reference
+// (the KEK secret for local, or the key ID/ARN/name for AWS KMS/GCP KMS/
+// Vault Transit) rather than whatever *_KEY_ID env var is live right now.
+// POST /settings/secrets/rotate uses this to unwrap DEKs that were wrapped
+// under a KEK that has since been retired from the env var config.
+func NewKeyProviderForRef(ctx context.Context, ref string) (KeyProvider, error) {
+	switch Kind(os.Getenv(providerEnvVar)) {
+	case KindAWSKMS:
+		return NewAWSKMSProvider(ctx, ref)
+	case KindGCPKMS:
+		return NewGCPKMSProvider(ctx, ref)
+	case KindVaultTransit:
+		return NewVaultTransitProvider(ref)
+	case KindLocal, "":
+		return newLocalProviderWithSecret(ref)
+	default:
+		return nil, fmt.Errorf("secrets: unknown provider kind %q", os.Getenv(providerEnvVar))
+	}
+}
@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/deepfence/ThreatMapper/deepfence_utils/log"
+)
+
+// EncryptConfigMap replaces each of sensitiveFields in config with the
+// base64 JSON encoding of its Envelope. Used by handler.AddIntegration right
+// before the config map is persisted, so the stored row only ever contains
+// ciphertext for webhook URLs, API tokens, SMTP passwords, etc.
+func EncryptConfigMap(ctx context.Context, provider KeyProvider, config map[string]interface{}, sensitiveFields []string) (map[string]interface{}, error) {
+	for _, field := range sensitiveFields {
+		raw, ok := config[field]
+		if !ok {
+			continue
+		}
+		plaintext, ok := raw.(string)
+		if !ok || plaintext == "" {
+			continue
+		}
+		env, err := Seal(ctx, provider, []byte(plaintext))
+		if err != nil {
+			return nil, fmt.Errorf("secrets: encrypt field %q: %w", field, err)
+		}
+		encoded, err := json.Marshal(env)
+		if err != nil {
+			return nil, err
+		}
+		config[field] = base64.StdEncoding.EncodeToString(encoded)
+	}
+	return config, nil
+}
+
+// DecryptConfigMap reverses EncryptConfigMap and audit-logs every decrypt, as
+// used by handler.GetIntegrations before redacting the response.
+func DecryptConfigMap(ctx context.Context, provider KeyProvider, config map[string]interface{}, sensitiveFields []string) (map[string]interface{}, error) {
+	for _, field := range sensitiveFields {
+		raw, ok := config[field]
+		if !ok {
+			continue
+		}
+		encoded, ok := raw.(string)
+		if !ok || encoded == "" {
+			continue
+		}
+		envBytes, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			// not yet encrypted (e.g. pre-migration row); leave as-is
+			continue
+		}
+		var env Envelope
+		if err := json.Unmarshal(envBytes, &env); err != nil {
+			continue
+		}
+		plaintext, err := Open(ctx, provider, env)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: decrypt field %q: %w", field, err)
+		}
+		log.Info().Msgf("secrets: decrypted sensitive integration config field %q (key_id=%s)", field, env.KeyID)
+		config[field] = string(plaintext)
+	}
+	return config, nil
+}
@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"context"
+
+	kmsapi "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+// gcpKMSProvider wraps/unwraps DEKs with a single GCP KMS CryptoKey,
+// identified by its full resource name.
+type gcpKMSProvider struct {
+	client      *kmsapi.KeyManagementClient
+	cryptoKeyID string
+}
+
+// NewGCPKMSProvider dials GCP KMS using application-default credentials and
+// targets the given CryptoKey resource name
+// (projects/*/locations/*/keyRings/*/cryptoKeys/*).
+func NewGCPKMSProvider(ctx context.Context, cryptoKeyID string) (KeyProvider, error) {
+	client, err := kmsapi.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gcpKMSProvider{client: client, cryptoKeyID: cryptoKeyID}, nil
+}
+
+func (p *gcpKMSProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	resp, err := p.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      p.cryptoKeyID,
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (p *gcpKMSProvider) UnwrapKey(ctx context.Context, wrappedDEK []byte) ([]byte, error) {
+	resp, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       p.cryptoKeyID,
+		Ciphertext: wrappedDEK,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (p *gcpKMSProvider) KeyID() string {
+	return p.cryptoKeyID
+}
@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LocalKEKEnvVar is the env var that seeds the local AES-GCM fallback KEK.
+// It is meant for dev and self-hosted installs without a cloud KMS; the KEK
+// never leaves the process.
+const LocalKEKEnvVar = "DEEPFENCE_SECRETS_LOCAL_KEK"
+
+// localProvider is the AES-GCM fallback KeyProvider: the KEK is derived by
+// hashing the configured env var so it's always exactly 32 bytes, and
+// wrapping a DEK is just AES-GCM-encrypting it under that KEK.
+type localProvider struct {
+	kek   [32]byte
+	keyID string
+}
+
+// NewLocalProvider reads LocalKEKEnvVar and fails closed if it is unset,
+// since a zero-value KEK would silently make every wrapped DEK recoverable
+// by anyone with read access to the database.
+func NewLocalProvider() (KeyProvider, error) {
+	secret := os.Getenv(LocalKEKEnvVar)
+	if secret == "" {
+		return nil, fmt.Errorf("%s is not set", LocalKEKEnvVar)
+	}
+	return newLocalProviderWithSecret(secret)
+}
+
+// newLocalProviderWithSecret builds a localProvider from an explicit KEK
+// secret rather than LocalKEKEnvVar, so POST /settings/secrets/rotate can
+// unwrap DEKs under a KEK that has since been rotated out of the env var.
+func newLocalProviderWithSecret(secret string) (KeyProvider, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("local KEK secret must not be empty")
+	}
+	kek := sha256.Sum256([]byte(secret))
+	return &localProvider{
+		kek:   kek,
+		keyID: "local:" + sha256sum8(kek[:]),
+	}, nil
+}
+
+func (p *localProvider) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	block, err := aes.NewCipher(p.kek[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, dek, nil), nil
+}
+
+func (p *localProvider) UnwrapKey(_ context.Context, wrappedDEK []byte) ([]byte, error) {
+	block, err := aes.NewCipher(p.kek[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedDEK) < gcm.NonceSize() {
+		return nil, errors.New("wrapped DEK too short")
+	}
+	nonce, ciphertext := wrappedDEK[:gcm.NonceSize()], wrappedDEK[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (p *localProvider) KeyID() string {
+	return p.keyID
+}
+
+func sha256sum8(b []byte) string {
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("%x", sum[:4])
+}
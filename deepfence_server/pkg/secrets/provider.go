@@ -0,0 +1,34 @@
+// Package secrets implements envelope encryption for integration config
+// fields (webhook URLs, API tokens, SMTP passwords, AWS keys, ...) that are
+// stored in Postgres. A per-row data encryption key (DEK) encrypts the field
+// value; the DEK itself is "wrapped" (encrypted) by a KeyProvider-backed key
+// encryption key (KEK) and stored alongside the ciphertext, so rotating the
+// KEK only means rewrapping DEKs, never re-encrypting every row's data.
+package secrets
+
+import "context"
+
+// KeyProvider wraps and unwraps data encryption keys using a key encryption
+// key it manages. Implementations back onto AWS KMS, GCP KMS, HashiCorp
+// Vault Transit, or a local AES-GCM fallback for dev/self-hosted setups that
+// don't have a cloud KMS available.
+type KeyProvider interface {
+	// WrapKey encrypts a freshly generated DEK under this provider's KEK.
+	WrapKey(ctx context.Context, dek []byte) (wrappedDEK []byte, err error)
+	// UnwrapKey decrypts a DEK previously wrapped by WrapKey.
+	UnwrapKey(ctx context.Context, wrappedDEK []byte) (dek []byte, err error)
+	// KeyID identifies the KEK currently in use, stored alongside wrapped
+	// DEKs so a rotation can tell which rows still need rewrapping.
+	KeyID() string
+}
+
+// Kind selects which KeyProvider backs the pkg/secrets subsystem, configured
+// via the DEEPFENCE_SECRETS_PROVIDER env var.
+type Kind string
+
+const (
+	KindLocal       Kind = "local"
+	KindAWSKMS      Kind = "aws-kms"
+	KindGCPKMS      Kind = "gcp-kms"
+	KindVaultTransit Kind = "vault-transit"
+)
@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// vaultTransitProvider wraps/unwraps DEKs using a Vault Transit named key,
+// so the KEK material never leaves Vault.
+type vaultTransitProvider struct {
+	client  *vaultapi.Client
+	keyName string
+}
+
+// NewVaultTransitProvider builds a provider against the given Transit key
+// name, using the ambient Vault client config (VAULT_ADDR, VAULT_TOKEN, ...).
+func NewVaultTransitProvider(keyName string) (KeyProvider, error) {
+	client, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &vaultTransitProvider{client: client, keyName: keyName}, nil
+}
+
+func (p *vaultTransitProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx, fmt.Sprintf("transit/encrypt/%s", p.keyName), map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(dek),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, _ := secret.Data["ciphertext"].(string)
+	return []byte(ciphertext), nil
+}
+
+func (p *vaultTransitProvider) UnwrapKey(ctx context.Context, wrappedDEK []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx, fmt.Sprintf("transit/decrypt/%s", p.keyName), map[string]interface{}{
+		"ciphertext": string(wrappedDEK),
+	})
+	if err != nil {
+		return nil, err
+	}
+	plaintextB64, _ := secret.Data["plaintext"].(string)
+	return base64.StdEncoding.DecodeString(plaintextB64)
+}
+
+func (p *vaultTransitProvider) KeyID() string {
+	return "vault-transit:" + p.keyName
+}
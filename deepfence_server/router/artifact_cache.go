@@ -0,0 +1,12 @@
+package router
+
+import (
+	"github.com/deepfence/ThreatMapper/deepfence_server/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// ArtifactCacheRoutes mounts the admin artifact-cache endpoints onto r.
+func ArtifactCacheRoutes(r chi.Router, h *handler.Handler) {
+	r.Post("/settings/artifact-cache/purge", h.PurgeArtifactCache)
+	r.Get("/settings/artifact-cache/stats", h.GetArtifactCacheStats)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/deepfence/ThreatMapper/deepfence_server/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// ExecutionRoutes mounts the executions API onto r. Registering it with
+// deepfence_server's router is left to the caller.
+func ExecutionRoutes(r chi.Router, h *handler.Handler) {
+	r.Get("/integration/{integration_id}/executions", h.ListIntegrationExecutions)
+	r.Get("/execution/{execution_id}", h.GetExecution)
+	r.Get("/execution/{execution_id}/log", h.GetExecutionLog)
+	r.Post("/execution/{execution_id}/stop", h.StopExecution)
+}
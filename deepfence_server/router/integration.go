@@ -0,0 +1,14 @@
+package router
+
+import (
+	"github.com/deepfence/ThreatMapper/deepfence_server/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// IntegrationRoutes mounts the integration configuration API onto r.
+func IntegrationRoutes(r chi.Router, h *handler.Handler) {
+	r.Post("/integration", h.AddIntegration)
+	r.Post("/integration/test", h.TestIntegration)
+	r.Get("/integration", h.GetIntegrations)
+	r.Delete("/integration/{integration_id}", h.DeleteIntegration)
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/deepfence/ThreatMapper/deepfence_server/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// ScheduledTaskRoutes mounts the user-defined scheduled-task API onto r.
+func ScheduledTaskRoutes(r chi.Router, h *handler.Handler) {
+	r.Post("/settings/scheduled-task", h.AddScheduledTask)
+	r.Get("/settings/scheduled-task", h.ListScheduledTasks)
+	r.Put("/settings/scheduled-task/{scheduled_task_id}", h.UpdateScheduledTask)
+	r.Delete("/settings/scheduled-task/{scheduled_task_id}", h.DeleteScheduledTask)
+	r.Post("/settings/scheduled-task/{scheduled_task_id}/enable", func(w http.ResponseWriter, r *http.Request) {
+		h.SetScheduledTaskEnabled(w, r, true)
+	})
+	r.Post("/settings/scheduled-task/{scheduled_task_id}/disable", func(w http.ResponseWriter, r *http.Request) {
+		h.SetScheduledTaskEnabled(w, r, false)
+	})
+	r.Post("/settings/scheduled-task/{scheduled_task_id}/run", h.RunScheduledTaskNow)
+}
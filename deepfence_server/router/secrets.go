@@ -0,0 +1,11 @@
+package router
+
+import (
+	"github.com/deepfence/ThreatMapper/deepfence_server/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// SecretsRoutes mounts the secrets-at-rest management API onto r.
+func SecretsRoutes(r chi.Router, h *handler.Handler) {
+	r.Post("/settings/secrets/rotate", h.RotateSecrets)
+}
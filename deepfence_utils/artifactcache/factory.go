@@ -0,0 +1,64 @@
+package artifactcache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// defaultMemoryStoreMaxEntries caps the singleton MemoryStore when
+// ARTIFACT_CACHE_MAX_ENTRIES isn't set.
+const defaultMemoryStoreMaxEntries = 128
+
+// defaultStore, defaultStoreOnce and defaultStoreErr back NewFromEnv, so
+// every caller within one process shares a single Store instance (MemoryStore
+// or ObjectStore alike). This matters beyond the allow-memory fallback: a
+// handler that calls NewFromEnv per-request (e.g. the stats/purge endpoints)
+// must observe the same hits/misses counters as whatever code in that
+// process actually uses the cache, not a freshly zeroed instance.
+var (
+	defaultStore     Store
+	defaultStoreOnce sync.Once
+	defaultStoreErr  error
+)
+
+// NewFromEnv builds the Store configured for this deployment via
+// ARTIFACT_CACHE_ENDPOINT (S3/GCS/MinIO). deepfence_server and
+// deepfence_worker are separate binaries and can't share an in-memory store,
+// so ARTIFACT_CACHE_ENDPOINT is required unless the deployment opts into a
+// single-process MemoryStore with ARTIFACT_CACHE_ALLOW_MEMORY=true.
+func NewFromEnv(ctx context.Context) (Store, error) {
+	endpoint := os.Getenv("ARTIFACT_CACHE_ENDPOINT")
+	if endpoint == "" {
+		allowMemory, _ := strconv.ParseBool(os.Getenv("ARTIFACT_CACHE_ALLOW_MEMORY"))
+		if !allowMemory {
+			return nil, fmt.Errorf("artifactcache: ARTIFACT_CACHE_ENDPOINT is required (deepfence_server and " +
+				"deepfence_worker are separate processes and cannot share an in-memory cache); set " +
+				"ARTIFACT_CACHE_ALLOW_MEMORY=true to opt into a single-process in-memory store instead")
+		}
+		defaultStoreOnce.Do(func() {
+			maxEntries := defaultMemoryStoreMaxEntries
+			if raw := os.Getenv("ARTIFACT_CACHE_MAX_ENTRIES"); raw != "" {
+				if parsed, err := strconv.Atoi(raw); err == nil {
+					maxEntries = parsed
+				}
+			}
+			defaultStore = NewMemoryStore(maxEntries)
+		})
+		return defaultStore, nil
+	}
+	defaultStoreOnce.Do(func() {
+		useSSL, _ := strconv.ParseBool(os.Getenv("ARTIFACT_CACHE_USE_SSL"))
+		defaultStore, defaultStoreErr = NewObjectStore(ctx, ObjectStoreConfig{
+			Endpoint:  endpoint,
+			AccessKey: os.Getenv("ARTIFACT_CACHE_ACCESS_KEY"),
+			SecretKey: os.Getenv("ARTIFACT_CACHE_SECRET_KEY"),
+			Bucket:    os.Getenv("ARTIFACT_CACHE_BUCKET"),
+			Prefix:    "artifactcache/",
+			UseSSL:    useSSL,
+		})
+	})
+	return defaultStore, defaultStoreErr
+}
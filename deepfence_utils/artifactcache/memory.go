@@ -0,0 +1,125 @@
+package artifactcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryStore is an in-process Store used by tests and by single-replica
+// dev setups. Entries are evicted on a strict LRU basis once maxEntries is
+// exceeded, and lazily on access once their TTL has passed.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front = most recently used
+
+	hits   uint64
+	misses uint64
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time // zero value means no expiry
+}
+
+// NewMemoryStore builds an in-memory Store capped at maxEntries; 0 means
+// unbounded (only appropriate for tests).
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) (io.ReadCloser, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		atomic.AddUint64(&m.misses, 1)
+		lookupsTotal.WithLabelValues("memory", "miss").Inc()
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.removeLocked(elem)
+		atomic.AddUint64(&m.misses, 1)
+		lookupsTotal.WithLabelValues("memory", "miss").Inc()
+		return nil, false, nil
+	}
+	m.order.MoveToFront(elem)
+	atomic.AddUint64(&m.hits, 1)
+	lookupsTotal.WithLabelValues("memory", "hit").Inc()
+	return io.NopCloser(bytes.NewReader(entry.data)), true, nil
+}
+
+func (m *MemoryStore) Put(_ context.Context, key string, data io.Reader, ttl time.Duration) error {
+	buf, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	if elem, ok := m.entries[key]; ok {
+		elem.Value.(*memoryEntry).data = buf
+		elem.Value.(*memoryEntry).expiresAt = expiresAt
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	elem := m.order.PushFront(&memoryEntry{key: key, data: buf, expiresAt: expiresAt})
+	m.entries[key] = elem
+
+	for m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		if oldest == nil {
+			break
+		}
+		m.removeLocked(oldest)
+	}
+	return nil
+}
+
+func (m *MemoryStore) Purge(_ context.Context, prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, elem := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.removeLocked(elem)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *MemoryStore) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&m.hits),
+		Misses: atomic.LoadUint64(&m.misses),
+	}
+}
+
+// removeLocked must be called with m.mu held.
+func (m *MemoryStore) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*memoryEntry)
+	delete(m.entries, entry.key)
+	m.order.Remove(elem)
+}
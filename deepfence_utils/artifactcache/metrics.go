@@ -0,0 +1,16 @@
+package artifactcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// lookupsTotal counts every Store.Get call by backend and outcome (hit/miss).
+// Stats() only reflects the calling process's own Store instance, which
+// isn't useful for the ObjectStore backend shared across deepfence_server
+// and deepfence_worker replicas; scraping this metric from every replica is
+// how the cluster-wide hit ratio is actually observed.
+var lookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "deepfence_artifactcache_lookups_total",
+	Help: "Artifact cache Get calls, by backend and outcome (hit/miss).",
+}, []string{"backend", "outcome"})
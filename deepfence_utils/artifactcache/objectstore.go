@@ -0,0 +1,124 @@
+package artifactcache
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// ObjectStore is a Store backed by any S3-compatible object store: AWS S3,
+// GCS (via its S3 interoperability endpoint), or a self-hosted MinIO. TTL is
+// recorded as object user-metadata rather than enforced inline, since object
+// stores don't expire individual keys on read the way MemoryStore does;
+// bucket lifecycle rules should be configured to reap expired objects.
+type ObjectStore struct {
+	client *minio.Client
+	bucket string
+	prefix string
+
+	hits   uint64
+	misses uint64
+}
+
+// ObjectStoreConfig configures an ObjectStore. Endpoint/AccessKey/SecretKey
+// point at S3, GCS's S3-compatible endpoint, or a MinIO deployment alike;
+// UseSSL should be true for anything but a local dev MinIO.
+type ObjectStoreConfig struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	// Prefix namespaces every key under e.g. "artifactcache/" so the bucket
+	// can be shared with unrelated objects.
+	Prefix string
+	UseSSL bool
+}
+
+// NewObjectStore dials the configured endpoint and ensures the bucket
+// exists.
+func NewObjectStore(ctx context.Context, cfg ObjectStoreConfig) (*ObjectStore, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return &ObjectStore{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (o *ObjectStore) objectName(key string) string {
+	return o.prefix + key
+}
+
+func (o *ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	obj, err := o.client.GetObject(ctx, o.bucket, o.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		atomic.AddUint64(&o.misses, 1)
+		lookupsTotal.WithLabelValues("object", "miss").Inc()
+		return nil, false, nil
+	}
+	// GetObject is lazy: the key only actually not-exists once we Stat it.
+	if _, err := obj.Stat(); err != nil {
+		atomic.AddUint64(&o.misses, 1)
+		lookupsTotal.WithLabelValues("object", "miss").Inc()
+		_ = obj.Close()
+		return nil, false, nil
+	}
+	atomic.AddUint64(&o.hits, 1)
+	lookupsTotal.WithLabelValues("object", "hit").Inc()
+	return obj, true, nil
+}
+
+func (o *ObjectStore) Put(ctx context.Context, key string, data io.Reader, ttl time.Duration) error {
+	userMeta := map[string]string{}
+	if ttl > 0 {
+		userMeta["expires-at"] = time.Now().Add(ttl).Format(time.RFC3339)
+	}
+	_, err := o.client.PutObject(ctx, o.bucket, o.objectName(key), data, -1, minio.PutObjectOptions{
+		UserMetadata: userMeta,
+	})
+	return err
+}
+
+func (o *ObjectStore) Purge(ctx context.Context, prefix string) (int, error) {
+	removed := 0
+	objectCh := o.client.ListObjects(ctx, o.bucket, minio.ListObjectsOptions{
+		Prefix:    o.objectName(prefix),
+		Recursive: true,
+	})
+	for obj := range objectCh {
+		if obj.Err != nil {
+			return removed, obj.Err
+		}
+		if !strings.HasPrefix(obj.Key, o.objectName(prefix)) {
+			continue
+		}
+		if err := o.client.RemoveObject(ctx, o.bucket, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (o *ObjectStore) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&o.hits),
+		Misses: atomic.LoadUint64(&o.misses),
+	}
+}
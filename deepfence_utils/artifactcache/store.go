@@ -0,0 +1,53 @@
+// Package artifactcache provides a cache for large artifacts (the
+// vulnerability DB, SBOMs, posture-provider metadata) shared across
+// deepfence_worker and deepfence_server. MemoryStore evicts by TTL and LRU;
+// ObjectStore records TTL as object metadata and evicts nothing itself.
+package artifactcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+)
+
+// Store is an artifact cache keyed by caller-chosen strings. KeyForContent
+// derives a content-hash key, but a Store does not require one: the worker
+// caches the vulnerability DB under the fixed key "vulnerability_db/latest".
+type Store interface {
+	// Get returns the artifact for key and whether it was found. A cache
+	// miss is not an error; ok is false and err is nil.
+	Get(ctx context.Context, key string) (r io.ReadCloser, ok bool, err error)
+	// Put stores data under key with the given TTL (0 means no expiry).
+	// MemoryStore also evicts least-recently-used entries once over
+	// capacity; ObjectStore has no capacity limit and no LRU eviction.
+	Put(ctx context.Context, key string, data io.Reader, ttl time.Duration) error
+	// Purge removes every entry whose key has the given prefix and returns
+	// how many were removed. Backs the admin purge-by-prefix endpoint.
+	Purge(ctx context.Context, prefix string) (int, error)
+	// Stats reports cumulative hit/miss counts for the hit-ratio metric.
+	Stats() Stats
+}
+
+// Stats is a point-in-time snapshot of a Store's hit/miss counters.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 if there have been no
+// lookups yet.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// KeyForContent returns the content-addressed cache key for data.
+func KeyForContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
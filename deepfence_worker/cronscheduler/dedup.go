@@ -0,0 +1,43 @@
+package cronscheduler
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/deepfence/ThreatMapper/deepfence_utils/directory"
+	"github.com/deepfence/ThreatMapper/deepfence_utils/log"
+	sdkUtils "github.com/deepfence/ThreatMapper/deepfence_utils/utils"
+)
+
+// DedupMiddleware drops a message already claimed for (task, trigger_id),
+// protecting against the broker redelivering the same message. Wired into
+// every task's handler by (*Scheduler).registerDedupMiddleware, so every
+// replica consuming that topic drops redeliveries idempotently, not just the
+// replica that happens to win leader election.
+func (s *Scheduler) DedupMiddleware(task string) message.Middleware {
+	return dedupMiddleware(s.leaders, task)
+}
+
+// dedupMiddleware is the leaderRegistry-parameterized form of
+// (*Scheduler).DedupMiddleware, split out so it can be unit-tested without a
+// full Scheduler.
+func dedupMiddleware(leaders *leaderRegistry, task string) message.Middleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+			triggerID := msg.Metadata.Get(sdkUtils.TriggerIDKey)
+			if triggerID == "" {
+				return h(msg)
+			}
+			claimed, err := claimTrigger(msg.Context(), task, triggerID)
+			if err != nil {
+				return nil, err
+			}
+			if !claimed {
+				log.Info().Msgf("dropping redelivered message for task %s, trigger %s", task, triggerID)
+				if namespace, err := directory.ExtractNamespace(msg.Context()); err == nil {
+					leaders.get(namespace).recordDuplicateTick(task)
+				}
+				return nil, nil
+			}
+			return h(msg)
+		}
+	}
+}
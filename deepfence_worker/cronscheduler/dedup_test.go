@@ -0,0 +1,25 @@
+package cronscheduler
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+func TestDedupMiddlewarePassesThroughWithoutTriggerID(t *testing.T) {
+	called := false
+	handler := func(msg *message.Message) ([]*message.Message, error) {
+		called = true
+		return nil, nil
+	}
+
+	wrapped := dedupMiddleware(newLeaderRegistry(), "test-task")(handler)
+	msg := message.NewMessage("1", nil)
+
+	if _, err := wrapped(msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected handler to be called for a message with no trigger id")
+	}
+}
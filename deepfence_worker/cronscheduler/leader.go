@@ -0,0 +1,146 @@
+package cronscheduler
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deepfence/ThreatMapper/deepfence_utils/directory"
+	"github.com/deepfence/ThreatMapper/deepfence_utils/log"
+)
+
+// leaderLockName is the advisory lock key namespace-scoped schedulers
+// contend for, so only one replica per tenant fires periodic ticks.
+const leaderLockName = "deepfence_worker_cron_leader"
+
+// leaderElection tracks whether this replica holds the Postgres advisory
+// lock for a single namespace. Session-level locks are tied to the physical
+// connection that took them, so refresh holds one reserved *sql.Conn for as
+// long as it keeps the lock instead of going through the pooled client.
+type leaderElection struct {
+	namespace directory.NamespaceID
+	lockKey   int64
+	isLeader  int32 // atomic bool
+
+	mu   sync.Mutex
+	conn *sql.Conn // reserved connection currently holding the lock, nil if not leader
+
+	missedTicks    uint64
+	duplicateTicks uint64
+}
+
+func newLeaderElection(namespace directory.NamespaceID) *leaderElection {
+	return &leaderElection{
+		namespace: namespace,
+		lockKey:   advisoryLockKey(leaderLockName, string(namespace)),
+	}
+}
+
+func advisoryLockKey(parts ...string) int64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return int64(h.Sum64())
+}
+
+// refresh (re-)acquires the advisory lock for this namespace and updates
+// IsLeader() accordingly; it never blocks. If the reserved connection from a
+// prior acquire is still alive, it just pings it instead of re-acquiring.
+func (le *leaderElection) refresh(ctx context.Context) error {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	if le.conn != nil {
+		if err := le.conn.PingContext(ctx); err == nil {
+			atomic.StoreInt32(&le.isLeader, 1)
+			return nil
+		}
+		le.conn.Close()
+		le.conn = nil
+		atomic.StoreInt32(&le.isLeader, 0)
+	}
+
+	db, err := directory.PostgresDB(ctx)
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", le.lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return err
+	}
+	if !acquired {
+		conn.Close()
+		atomic.StoreInt32(&le.isLeader, 0)
+		return nil
+	}
+	le.conn = conn
+	atomic.StoreInt32(&le.isLeader, 1)
+	return nil
+}
+
+func (le *leaderElection) IsLeader() bool {
+	return atomic.LoadInt32(&le.isLeader) == 1
+}
+
+func (le *leaderElection) recordMissedTick(task string) {
+	atomic.AddUint64(&le.missedTicks, 1)
+	missedTicksTotal.WithLabelValues(task).Inc()
+}
+
+func (le *leaderElection) recordDuplicateTick(task string) {
+	atomic.AddUint64(&le.duplicateTicks, 1)
+	duplicateTicksTotal.WithLabelValues(task).Inc()
+}
+
+// leaderRegistry owns one leaderElection per tenant namespace.
+type leaderRegistry struct {
+	sync.Mutex
+	byNamespace map[directory.NamespaceID]*leaderElection
+}
+
+func newLeaderRegistry() *leaderRegistry {
+	return &leaderRegistry{byNamespace: make(map[directory.NamespaceID]*leaderElection)}
+}
+
+func (r *leaderRegistry) get(namespace directory.NamespaceID) *leaderElection {
+	r.Lock()
+	defer r.Unlock()
+	le, ok := r.byNamespace[namespace]
+	if !ok {
+		le = newLeaderElection(namespace)
+		r.byNamespace[namespace] = le
+	}
+	return le
+}
+
+// runElectionLoop periodically renews the advisory lock for every namespace
+// this replica has seen so far.
+func (r *leaderRegistry) runElectionLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		r.Lock()
+		namespaces := make([]directory.NamespaceID, 0, len(r.byNamespace))
+		for ns := range r.byNamespace {
+			namespaces = append(namespaces, ns)
+		}
+		r.Unlock()
+		for _, ns := range namespaces {
+			le := r.get(ns)
+			ctx := directory.NewContextWithNameSpace(ns)
+			if err := le.refresh(ctx); err != nil {
+				log.Error().Err(err).Msgf("leader election refresh failed for namespace %s", ns)
+			}
+		}
+	}
+}
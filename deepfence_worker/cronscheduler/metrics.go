@@ -0,0 +1,21 @@
+package cronscheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// missedTicksTotal counts ticks a replica's enqueueTask/enqueueScheduledTask
+// closure skipped because it did not hold the namespace's advisory lock.
+var missedTicksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "deepfence_worker_cron_missed_ticks_total",
+	Help: "Periodic cron ticks skipped by a non-leader replica, by task.",
+}, []string{"task"})
+
+// duplicateTicksTotal counts (task, trigger_id) pairs that were rejected by
+// the Postgres de-dup guard because the same trigger had already been
+// published within the retention window.
+var duplicateTicksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "deepfence_worker_cron_duplicate_ticks_total",
+	Help: "Cron ticks dropped as replays of an already-published trigger_id, by task.",
+}, []string{"task"})
@@ -1,24 +1,38 @@
 package cronscheduler
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"io"
 	stdLogger "log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/deepfence/ThreatMapper/deepfence_utils/artifactcache"
 	"github.com/deepfence/ThreatMapper/deepfence_utils/directory"
 	"github.com/deepfence/ThreatMapper/deepfence_utils/log"
 	postgresqlDb "github.com/deepfence/ThreatMapper/deepfence_utils/postgresql/postgresql-db"
 	sdkUtils "github.com/deepfence/ThreatMapper/deepfence_utils/utils"
 	"github.com/deepfence/ThreatMapper/deepfence_utils/vulnerability_db"
 	"github.com/deepfence/ThreatMapper/deepfence_worker/utils"
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
+// leaderElectionInterval controls how often a replica re-attempts the
+// Postgres advisory lock that gates periodic tick ownership.
+const leaderElectionInterval = 10 * time.Second
+
 type ScheduledJobs struct {
 	jobHashToId map[string]cron.EntryID
 	jobHashes   []string
@@ -26,13 +40,20 @@ type ScheduledJobs struct {
 }
 
 type Scheduler struct {
-	cron           *cron.Cron
-	tasksPublisher *kafka.Publisher
-	scheduledJobs  ScheduledJobs
+	cron              *cron.Cron
+	tasksPublisher    *kafka.Publisher
+	scheduledJobs     ScheduledJobs
+	userScheduledJobs ScheduledJobs
+	leaders           *leaderRegistry
+	artifactCache     artifactcache.Store
 }
 
 func NewScheduler(tasksPublisher *kafka.Publisher) (*Scheduler, error) {
 	logger := stdLogger.New(os.Stdout, "cron: ", stdLogger.LstdFlags)
+	cache, err := artifactcache.NewFromEnv(context.Background())
+	if err != nil {
+		return nil, err
+	}
 	scheduler := &Scheduler{
 		cron: cron.New(
 			cron.WithSeconds(),
@@ -44,32 +65,182 @@ func NewScheduler(tasksPublisher *kafka.Publisher) (*Scheduler, error) {
 			jobHashToId: make(map[string]cron.EntryID),
 			jobHashes:   []string{},
 		},
+		userScheduledJobs: ScheduledJobs{
+			jobHashToId: make(map[string]cron.EntryID),
+			jobHashes:   []string{},
+		},
+		leaders:       newLeaderRegistry(),
+		artifactCache: cache,
 	}
 	return scheduler, nil
 }
 
-func (s *Scheduler) Init() {
+// Init bootstraps the scheduler's periodic ticks and leader election.
+// router is the worker's Kafka consumer router: every task topic's handler
+// (registered by the caller with the task name as its handler name) gets
+// wrapped with DedupMiddleware so a broker-redelivered tick is dropped on
+// whichever replica's consumer receives the redelivery, not just the replica
+// currently holding the namespace's advisory lock.
+func (s *Scheduler) Init(router *message.Router) {
 	directory.ForEachNamespace(func(ctx context.Context) (string, error) {
 		return "scheduler addJobs", s.addJobs(ctx)
 	})
 	directory.ForEachNamespace(func(ctx context.Context) (string, error) {
 		return "scheduler startImmediately", StartInitJobs(ctx, s.tasksPublisher)
 	})
+	s.registerDedupMiddleware(router)
 	go s.updateScheduledJobs()
+	go s.leaders.runElectionLoop(leaderElectionInterval)
 }
 
-func (s *Scheduler) updateScheduledJobs() {
-	directory.ForEachNamespace(func(ctx context.Context) (string, error) {
-		return "Add scheduled jobs", s.addScheduledJobs(ctx)
-	})
+// allTaskTopics lists the Kafka task topics that get DedupMiddleware: every
+// task addJobs schedules, plus sdkUtils.ScheduledTasks for user and built-in
+// schedules. SetUpGraphDBTask, which only StartInitJobs enqueues, is not in it.
+var allTaskTopics = []string{
+	sdkUtils.TriggerConsoleActionsTask,
+	sdkUtils.CleanUpGraphDBTask,
+	sdkUtils.ComputeThreatTask,
+	sdkUtils.RetryFailedScansTask,
+	sdkUtils.RetryFailedUpgradesTask,
+	sdkUtils.CleanUpPostgresqlTask,
+	sdkUtils.CleanupDiagnosisLogs,
+	sdkUtils.CloudComplianceTask,
+	sdkUtils.CheckAgentUpgradeTask,
+	sdkUtils.SyncRegistryTask,
+	sdkUtils.SendNotificationTask,
+	sdkUtils.ReportCleanUpTask,
+	sdkUtils.CachePostureProviders,
+	sdkUtils.LinkCloudResourceTask,
+	sdkUtils.LinkNodesTask,
+	sdkUtils.ScheduledTasks,
+}
 
-	ticker := time.NewTicker(15 * time.Minute)
-	defer ticker.Stop()
+// registerDedupMiddleware wires DedupMiddleware into router for every task
+// topic, so de-duping actually happens on the consumer side instead of being
+// unreachable code that s.DedupMiddleware merely makes available.
+func (s *Scheduler) registerDedupMiddleware(router *message.Router) {
+	for _, task := range allTaskTopics {
+		router.AddHandlerMiddleware(task, s.DedupMiddleware(task))
+	}
+}
 
-	for range ticker.C {
+// scheduledTaskNotifyChannel mirrors model.scheduledTaskNotifyChannel on the
+// deepfence_server side: the handler NOTIFYs it on every create/update/
+// delete/enable/disable of a user-defined schedule.
+const scheduledTaskNotifyChannel = "scheduled_task_updates"
+
+func (s *Scheduler) updateScheduledJobs() {
+	refresh := func() {
 		directory.ForEachNamespace(func(ctx context.Context) (string, error) {
 			return "Add scheduled jobs", s.addScheduledJobs(ctx)
 		})
+		directory.ForEachNamespace(func(ctx context.Context) (string, error) {
+			return "Add user scheduled jobs", s.addUserScheduledJobs(ctx)
+		})
+	}
+	refresh()
+
+	go s.listenScheduledTaskUpdates(refresh)
+
+	// correctness fallback in case a NOTIFY is dropped
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		refresh()
+	}
+}
+
+// scheduledTaskNotification mirrors model.scheduledTaskNotification on the
+// deepfence_server side.
+type scheduledTaskNotification struct {
+	Type string `json:"type"`
+	ID   int32  `json:"id,omitempty"`
+}
+
+// listenScheduledTaskUpdates blocks on a Postgres LISTEN for
+// scheduledTaskNotifyChannel and acts on every notification's payload. It
+// reconnects with a short backoff if the listener connection drops.
+func (s *Scheduler) listenScheduledTaskUpdates(refresh func()) {
+	directory.ForEachNamespace(func(ctx context.Context) (string, error) {
+		namespace, err := directory.ExtractNamespace(ctx)
+		if err != nil {
+			return "scheduler listenScheduledTaskUpdates", err
+		}
+		go func(ctx context.Context) {
+			for {
+				pgClient, err := directory.PostgresClient(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("listenScheduledTaskUpdates: get postgres client")
+					time.Sleep(5 * time.Second)
+					continue
+				}
+				notifications, err := pgClient.Listen(ctx, scheduledTaskNotifyChannel)
+				if err != nil {
+					log.Error().Err(err).Msg("listenScheduledTaskUpdates: listen")
+					time.Sleep(5 * time.Second)
+					continue
+				}
+				for payload := range notifications {
+					var notification scheduledTaskNotification
+					if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+						log.Error().Err(err).Msg("listenScheduledTaskUpdates: decode notification payload")
+						continue
+					}
+					if notification.Type == "run_now" {
+						s.runScheduledTaskNow(ctx, namespace, notification.ID)
+						continue
+					}
+					refresh()
+				}
+				// channel closed: connection dropped, reconnect
+				time.Sleep(5 * time.Second)
+			}
+		}(ctx)
+		return "scheduler listenScheduledTaskUpdates", nil
+	})
+}
+
+// runScheduledTaskNow fires one off-cycle run of a user-defined scheduled
+// task, backing the "run now" control. Only the replica holding namespace's
+// advisory lock actually publishes.
+func (s *Scheduler) runScheduledTaskNow(ctx context.Context, namespace directory.NamespaceID, id int32) {
+	leader := s.leaders.get(namespace)
+	if !leader.IsLeader() {
+		leader.recordMissedTick(sdkUtils.ScheduledTasks)
+		return
+	}
+
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("runScheduledTaskNow: get postgres client")
+		return
+	}
+	task, err := pgClient.GetScheduledTask(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("runScheduledTaskNow: get scheduled task %d", id)
+		return
+	}
+	if !task.IsEnabled {
+		log.Info().Msgf("runScheduledTaskNow: scheduled task %d is disabled, skipping", id)
+		return
+	}
+
+	triggerID := uuid.New().String()
+	metadata := map[string]string{
+		directory.NamespaceKey: string(namespace),
+		sdkUtils.TriggerIDKey:  triggerID,
+	}
+	message := map[string]interface{}{
+		"action":      task.Action,
+		"id":          task.ID,
+		"payload":     task.Payload,
+		"description": task.Description,
+		"trigger_id":  triggerID,
+		"run_now":     true,
+	}
+	messageJson, _ := json.Marshal(message)
+	if err := utils.PublishNewJob(s.tasksPublisher, metadata, sdkUtils.ScheduledTasks, messageJson); err != nil {
+		log.Error().Msg(err.Error())
 	}
 }
 
@@ -114,7 +285,7 @@ func (s *Scheduler) addScheduledJobs(ctx context.Context) error {
 		newJobHashToId[jobHash] = jobId
 	}
 	for _, oldJobHash := range s.scheduledJobs.jobHashes {
-		if !sdkUtils.InSlice(oldJobHash, s.scheduledJobs.jobHashes) {
+		if !sdkUtils.InSlice(oldJobHash, newHashes) {
 			s.cron.Remove(s.scheduledJobs.jobHashToId[oldJobHash])
 		}
 	}
@@ -123,82 +294,311 @@ func (s *Scheduler) addScheduledJobs(ctx context.Context) error {
 	return nil
 }
 
+// userScheduledJobHash identifies a scheduled_tasks row's current shape, so
+// addUserScheduledJobs only re-registers a cron.AddFunc when something about
+// it actually changed - the same reconcile-by-hash role
+// sdkUtils.GetScheduledJobHash plays for the pre-existing Scheduler table.
+func userScheduledJobHash(task postgresqlDb.ScheduledTask) string {
+	return strconv.Itoa(int(task.ID)) + ":" + task.CronExpr + ":" + task.Action + ":" + string(task.Payload) + ":" + strconv.FormatBool(task.IsEnabled)
+}
+
+// addUserScheduledJobs reconciles cron.Cron's registered jobs against the
+// enabled scheduled_tasks rows written by the user-defined schedule CRUD API
+// (model.ScheduledTaskReq), the same reconcile-by-hash pattern
+// addScheduledJobs uses for the pre-existing Scheduler table. Without this,
+// a schedule created via that API never fired on its own cron_expr - only
+// its "run now" button (runScheduledTaskNow) ever read the table.
+func (s *Scheduler) addUserScheduledJobs(ctx context.Context) error {
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		return err
+	}
+	tasks, err := pgClient.ListEnabledScheduledTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.userScheduledJobs.Lock()
+	defer s.userScheduledJobs.Unlock()
+
+	namespace, err := directory.ExtractNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	var newHashes []string
+	newJobHashToId := make(map[string]cron.EntryID)
+	for _, task := range tasks {
+		jobHash := userScheduledJobHash(task)
+		if sdkUtils.InSlice(jobHash, s.userScheduledJobs.jobHashes) {
+			newHashes = append(newHashes, jobHash)
+			newJobHashToId[jobHash] = s.userScheduledJobs.jobHashToId[jobHash]
+			continue
+		}
+		jobId, err := s.cron.AddFunc(task.CronExpr, s.enqueueUserScheduledTask(namespace, task))
+		if err != nil {
+			return err
+		}
+		newHashes = append(newHashes, jobHash)
+		newJobHashToId[jobHash] = jobId
+	}
+	for _, oldJobHash := range s.userScheduledJobs.jobHashes {
+		if !sdkUtils.InSlice(oldJobHash, newHashes) {
+			s.cron.Remove(s.userScheduledJobs.jobHashToId[oldJobHash])
+		}
+	}
+	s.userScheduledJobs.jobHashes = newHashes
+	s.userScheduledJobs.jobHashToId = newJobHashToId
+	return nil
+}
+
 func (s *Scheduler) addJobs(ctx context.Context) error {
 	namespace, err := directory.ExtractNamespace(ctx)
 	if err != nil {
 		return err
 	}
+	leader := s.leaders.get(namespace)
 	log.Info().Msg("Register cronjobs")
 	// Documentation: https://pkg.go.dev/github.com/robfig/cron#hdr-Usage
-	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.TriggerConsoleActionsTask))
+	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.TriggerConsoleActionsTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CleanUpGraphDBTask))
+	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CleanUpGraphDBTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.ComputeThreatTask))
+	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.ComputeThreatTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.RetryFailedScansTask))
+	_, err = s.cron.AddFunc("@every 120s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.RetryFailedScansTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 10m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.RetryFailedUpgradesTask))
+	_, err = s.cron.AddFunc("@every 10m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.RetryFailedUpgradesTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 5m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CleanUpPostgresqlTask))
+	_, err = s.cron.AddFunc("@every 5m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CleanUpPostgresqlTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CleanupDiagnosisLogs))
+	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CleanupDiagnosisLogs))
 	if err != nil {
 		return err
 	}
 	// Adding CloudComplianceTask only to ensure data is ingested if task fails on startup, Retry to be handled by watermill
-	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CloudComplianceTask))
+	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CloudComplianceTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CheckAgentUpgradeTask))
+	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CheckAgentUpgradeTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 12h", enqueueTask(s.tasksPublisher, namespace, sdkUtils.SyncRegistryTask))
+	_, err = s.cron.AddFunc("@every 12h", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.SyncRegistryTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.SendNotificationTask))
+	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.SendNotificationTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.ReportCleanUpTask))
+	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.ReportCleanUpTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, sdkUtils.CachePostureProviders))
+	_, err = s.cron.AddFunc("@every 60m", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.CachePostureProviders))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.LinkCloudResourceTask))
+	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.LinkCloudResourceTask))
 	if err != nil {
 		return err
 	}
-	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, sdkUtils.LinkNodesTask))
+	_, err = s.cron.AddFunc("@every 30s", enqueueTask(s.tasksPublisher, namespace, leader, sdkUtils.LinkNodesTask))
 	if err != nil {
 		return err
 	}
 	// download updated vulnerability database
-	_, err = s.cron.AddFunc("@every 120m", vulnerability_db.DownloadDatabase)
+	_, err = s.cron.AddFunc("@every 120m", s.leaderGatedDownloadVulnerabilityDB(leader))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// vulnerabilityDBDownloadTick labels the leader-gated download tick in the
+// missed-tick metric; it isn't a Kafka task topic like sdkUtils' constants,
+// downloadVulnerabilityDB runs in-process rather than publishing a message.
+const vulnerabilityDBDownloadTick = "vulnerability_db_download"
+
+// leaderGatedDownloadVulnerabilityDB wraps downloadVulnerabilityDB the same
+// way enqueueTask wraps every other periodic tick: only the replica holding
+// the namespace's advisory lock runs it, so every replica doesn't
+// independently download and recache the same vulnerability DB.
+func (s *Scheduler) leaderGatedDownloadVulnerabilityDB(leader *leaderElection) func() {
+	return func() {
+		if leader != nil && !leader.IsLeader() {
+			leader.recordMissedTick(vulnerabilityDBDownloadTick)
+			return
+		}
+		s.downloadVulnerabilityDB()
+	}
+}
+
+// vulnerabilityDBCacheKey caches the refreshed vulnerability DB itself, not
+// just a marker that a download happened.
+const vulnerabilityDBCacheKey = "vulnerability_db/latest"
+
+func (s *Scheduler) downloadVulnerabilityDB() {
+	ctx := context.Background()
+	dbDir := vulnerability_db.DBDir()
+
+	if r, ok, err := s.artifactCache.Get(ctx, vulnerabilityDBCacheKey); err == nil && ok {
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to read cached vulnerability db")
+		} else if err := extractTarGz(data, dbDir); err != nil {
+			log.Error().Err(err).Msg("failed to extract cached vulnerability db to disk")
+		} else {
+			log.Info().Msg("vulnerability db already refreshed by another replica this period, extracted from cache")
+			return
+		}
+	}
+
+	before, _ := latestModTime(dbDir)
+
+	vulnerability_db.DownloadDatabase()
+
+	after, err := latestModTime(dbDir)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to stat downloaded vulnerability db for caching")
+		return
+	}
+	if !before.IsZero() && !after.After(before) {
+		// refresh failed and fell back to the existing copy: don't recache it
+		log.Info().Msg("vulnerability db unchanged after refresh attempt, not recaching")
+		return
+	}
+
+	data, err := archiveTarGz(dbDir)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to archive downloaded vulnerability db for caching")
+		return
+	}
+	if err := s.artifactCache.Put(ctx, vulnerabilityDBCacheKey, bytes.NewReader(data), 120*time.Minute); err != nil {
+		log.Error().Err(err).Msg("failed to cache vulnerability db")
+	}
+}
+
+// latestModTime returns the most recent modification time among all regular
+// files under dir (the grype-style vulnerability DB is a directory of
+// several files, not one, so the directory's own mtime isn't reliable).
+func latestModTime(dir string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return time.Time{}, err
+	}
+	return latest, nil
+}
+
+// archiveTarGz packs every regular file under dir into a gzipped tar, so the
+// whole multi-file vulnerability DB directory round-trips through the
+// artifact cache as a single blob.
+func archiveTarGz(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// extractTarGz unpacks an archive produced by archiveTarGz into dir,
+// overwriting any existing files.
+func extractTarGz(data []byte, dir string) error {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, header.Name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
+
 func StartInitJobs(ctx context.Context, taskPub *kafka.Publisher) error {
 	namespace, err := directory.ExtractNamespace(ctx)
 	if err != nil {
@@ -211,12 +611,12 @@ func StartInitJobs(ctx context.Context, taskPub *kafka.Publisher) error {
 	}
 
 	log.Info().Msgf("Start immediate cronjobs for namespace %s", namespace)
-	enqueueTask(taskPub, namespace, sdkUtils.SetUpGraphDBTask)()
-	enqueueTask(taskPub, namespace, sdkUtils.CheckAgentUpgradeTask)()
-	enqueueTask(taskPub, namespace, sdkUtils.SyncRegistryTask)()
-	enqueueTask(taskPub, namespace, sdkUtils.CloudComplianceTask)()
-	enqueueTask(taskPub, namespace, sdkUtils.ReportCleanUpTask)()
-	enqueueTask(taskPub, namespace, sdkUtils.CachePostureProviders)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.SetUpGraphDBTask)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.CheckAgentUpgradeTask)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.SyncRegistryTask)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.CloudComplianceTask)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.ReportCleanUpTask)()
+	enqueueTask(taskPub, namespace, nil, sdkUtils.CachePostureProviders)()
 
 	return nil
 }
@@ -226,30 +626,185 @@ func (s *Scheduler) Run() {
 }
 
 func (s *Scheduler) enqueueScheduledTask(namespace directory.NamespaceID, schedule postgresqlDb.Scheduler, payload map[string]string) func() {
+	leader := s.leaders.get(namespace)
 	log.Info().Msgf("Registering task: %s, %s for namespace %s", schedule.Description, schedule.CronExpr, namespace)
 	return func() {
-		log.Info().Msgf("Enqueuing task: %s, %s for namespace %s",
-			schedule.Description, schedule.CronExpr, namespace)
-		metadata := map[string]string{directory.NamespaceKey: string(namespace)}
+		if !leader.IsLeader() {
+			leader.recordMissedTick(sdkUtils.ScheduledTasks)
+			return
+		}
+		triggerID := uuid.New().String()
+		ctx := directory.NewContextWithNameSpace(namespace)
+
+		executionID, err := s.createExecution(ctx, schedule)
+		if err != nil {
+			log.Error().Msgf("failed to record execution for %s: %s", schedule.Description, err.Error())
+		}
+
+		log.Info().Msgf("Enqueuing task: %s, %s for namespace %s, trigger %s, execution %d",
+			schedule.Description, schedule.CronExpr, namespace, triggerID, executionID)
+		metadata := map[string]string{
+			directory.NamespaceKey: string(namespace),
+			sdkUtils.TriggerIDKey:  triggerID,
+		}
 		message := map[string]interface{}{
-			"action":      schedule.Action,
-			"id":          schedule.ID,
-			"payload":     payload,
-			"description": schedule.Description,
+			"action":       schedule.Action,
+			"id":           schedule.ID,
+			"payload":      payload,
+			"description":  schedule.Description,
+			"trigger_id":   triggerID,
+			"execution_id": executionID,
 		}
 		messageJson, _ := json.Marshal(message)
-		err := utils.PublishNewJob(s.tasksPublisher, metadata, sdkUtils.ScheduledTasks, messageJson)
+		err = utils.PublishNewJob(s.tasksPublisher, metadata, sdkUtils.ScheduledTasks, messageJson)
+		s.markExecutionEnqueued(ctx, executionID, err)
 		if err != nil {
 			log.Error().Msg(err.Error())
 		}
 	}
 }
 
-func enqueueTask(taskPub *kafka.Publisher, namespace directory.NamespaceID, task string) func() {
+// enqueueUserScheduledTask publishes task on its own cron_expr, mirroring the
+// message shape runScheduledTaskNow already publishes for the manual
+// "run now" control - just without the "run_now" flag, so a handler can't
+// tell a periodic fire from an off-cycle one except by that field's absence.
+func (s *Scheduler) enqueueUserScheduledTask(namespace directory.NamespaceID, task postgresqlDb.ScheduledTask) func() {
+	leader := s.leaders.get(namespace)
+	log.Info().Msgf("Registering user scheduled task: %s, %s for namespace %s", task.Description, task.CronExpr, namespace)
+	return func() {
+		if !leader.IsLeader() {
+			leader.recordMissedTick(sdkUtils.ScheduledTasks)
+			return
+		}
+		triggerID := uuid.New().String()
+		metadata := map[string]string{
+			directory.NamespaceKey: string(namespace),
+			sdkUtils.TriggerIDKey:  triggerID,
+		}
+		message := map[string]interface{}{
+			"action":      task.Action,
+			"id":          task.ID,
+			"payload":     task.Payload,
+			"description": task.Description,
+			"trigger_id":  triggerID,
+		}
+		messageJson, _ := json.Marshal(message)
+		if err := utils.PublishNewJob(s.tasksPublisher, metadata, sdkUtils.ScheduledTasks, messageJson); err != nil {
+			log.Error().Msg(err.Error())
+		}
+	}
+}
+
+// markExecutionEnqueued moves an Execution past "pending" once enqueuing is
+// actually decided: "error" if the publish itself failed (so a broker outage
+// doesn't leave the row stuck forever), otherwise "running", since the task
+// handler that consumes execution_id off the message is the one that knows
+// the real outcome and is responsible for the success/error transition and
+// any retries once it picks the job up.
+func (s *Scheduler) markExecutionEnqueued(ctx context.Context, executionID int64, publishErr error) {
+	if executionID == 0 {
+		return
+	}
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("markExecutionEnqueued: get postgres client")
+		return
+	}
+	status := "running"
+	if publishErr != nil {
+		status = "error"
+		if logErr := pgClient.AppendExecutionLog(ctx, postgresqlDb.AppendExecutionLogParams{
+			ID:   executionID,
+			Line: "enqueue failed: " + publishErr.Error(),
+		}); logErr != nil {
+			log.Error().Err(logErr).Msg("markExecutionEnqueued: append execution log")
+		}
+	}
+	if err := pgClient.UpdateExecutionStatus(ctx, postgresqlDb.UpdateExecutionStatusParams{
+		ID:     executionID,
+		Status: status,
+	}); err != nil {
+		log.Error().Err(err).Msg("markExecutionEnqueued: update execution status")
+	}
+}
+
+// createExecution records a pending Execution row for this tick so its
+// progress, retries, and logs are observable through
+// GET /execution/{id} and GET /execution/{id}/log, instead of only ever
+// appearing in the worker's own logs.
+func (s *Scheduler) createExecution(ctx context.Context, schedule postgresqlDb.Scheduler) (int64, error) {
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	row, err := pgClient.CreateExecution(ctx, postgresqlDb.CreateExecutionParams{
+		VendorType: "cron",
+		VendorID:   schedule.ID,
+		Trigger:    "schedule",
+		Status:     "pending",
+	})
+	if err != nil {
+		return 0, err
+	}
+	return row.ID, nil
+}
+
+// triggerDedupRetention bounds how long a (task, trigger_id) claim guards
+// against replay. No external job prunes scheduler_trigger_dedup, so
+// claimTrigger itself deletes rows past this window on every call - a
+// message can't plausibly still be redelivered this long after its tick
+// fired, and without this the table (and the "ignored within a window"
+// guarantee) would otherwise grow and hold forever.
+const triggerDedupRetention = 24 * time.Hour
+
+// claimTrigger records (task, triggerID) in scheduler_trigger_dedup and
+// reports whether this is the first time that pair has been seen. It backs
+// DedupMiddleware: called on the consumer side, keyed by the trigger_id the
+// producer stamped into the message, so a message the broker redelivers is
+// claimed once and dropped on every subsequent delivery. sqlc's :one
+// INSERT ... ON CONFLICT DO NOTHING RETURNING surfaces a conflict as
+// sql.ErrNoRows, which is treated as "not claimed" rather than a real error.
+func claimTrigger(ctx context.Context, task, triggerID string) (bool, error) {
+	pgClient, err := directory.PostgresClient(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := pgClient.DeleteOldTriggerDedup(ctx, time.Now().Add(-triggerDedupRetention)); err != nil {
+		log.Error().Err(err).Msg("claimTrigger: prune scheduler_trigger_dedup")
+	}
+	_, err = pgClient.InsertTriggerIfAbsent(ctx, postgresqlDb.InsertTriggerIfAbsentParams{
+		Task:      task,
+		TriggerID: triggerID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// enqueueTask registers a periodic tick. When leader is non-nil, the tick is
+// only published if this replica currently holds the namespace's advisory
+// lock, so the same @every job is not fired once per replica. Every message
+// carries a unique trigger id in sdkUtils.TriggerIDKey metadata; if the
+// broker redelivers that same message, DedupMiddleware on the consumer side
+// claims (task, trigger_id) and drops the redelivery.
+func enqueueTask(taskPub *kafka.Publisher, namespace directory.NamespaceID, leader *leaderElection, task string) func() {
 	log.Info().Msgf("Registering task: %s for namespace %s", task, namespace)
 	return func() {
-		log.Info().Msgf("Enqueuing task: %s for namespace %s", task, namespace)
-		metadata := map[string]string{directory.NamespaceKey: string(namespace)}
+		if leader != nil && !leader.IsLeader() {
+			leader.recordMissedTick(task)
+			return
+		}
+		triggerID := uuid.New().String()
+
+		log.Info().Msgf("Enqueuing task: %s for namespace %s, trigger %s", task, namespace, triggerID)
+		metadata := map[string]string{
+			directory.NamespaceKey: string(namespace),
+			sdkUtils.TriggerIDKey:  triggerID,
+		}
 		err := utils.PublishNewJob(taskPub, metadata, task,
 			[]byte(strconv.FormatInt(sdkUtils.GetTimestamp(), 10)))
 		if err != nil {